@@ -0,0 +1,140 @@
+package main
+
+import (
+	"math/big"
+	"math/rand"
+)
+
+// Lazily enumerates all arrangements of an adapter chain, see
+// `AdapterChain.Iterate`.
+type ArrangementIterator struct {
+	chain *AdapterChain
+
+	// Current path of the depth-first search, starting at the device and
+	// following `reachableFrom` towards the outlet.
+	stack []iteratorFrame
+}
+
+type iteratorFrame struct {
+	rating int
+	// Index of the next source within `reachableFrom[rating]` to explore.
+	next int
+}
+
+// Return an iterator over all arrangements of adapters which connect the
+// outlet to the device.
+//
+// Arrangements are only generated once requested, which is crucial as there
+// might well be more of them than fit into memory.
+func (chain *AdapterChain) Iterate() *ArrangementIterator {
+	return &ArrangementIterator{
+		chain: chain,
+		stack: []iteratorFrame{{rating: chain.Device()}},
+	}
+}
+
+// Return the next arrangement, as a list of ratings in ascending order which
+// starts with the outlet and ends with the device. Returns false once all
+// arrangements were returned.
+func (iterator *ArrangementIterator) Next() ([]int, bool) {
+	// We search backwards from the device, as every adapter can be reached
+	// from the outlet. As such every path we follow is part of a valid
+	// arrangement, and we never need to backtrack without having found
+	// one.
+	for len(iterator.stack) > 0 {
+		top := &iterator.stack[len(iterator.stack)-1]
+
+		if top.rating == iterator.chain.Outlet() {
+			arrangement := make([]int, len(iterator.stack))
+			for idx, frame := range iterator.stack {
+				arrangement[len(arrangement)-1-idx] = frame.rating
+			}
+
+			iterator.stack = iterator.stack[:len(iterator.stack)-1]
+			return arrangement, true
+		}
+
+		sources := iterator.chain.reachableFrom[top.rating]
+		if top.next < len(sources) {
+			source := sources[top.next]
+			top.next += 1
+			iterator.stack = append(iterator.stack, iteratorFrame{rating: source})
+		} else {
+			// All arrangements via this adapter were returned
+			iterator.stack = iterator.stack[:len(iterator.stack)-1]
+		}
+	}
+
+	return nil, false
+}
+
+// Pick an arrangement uniformly at random, in the format returned by
+// `ArrangementIterator.Next`.
+func (chain *AdapterChain) Sample(rng *rand.Rand) []int {
+	// Walking backwards from the device, we pick each predecessor with
+	// probability proportional to the number of ways in which it can be
+	// reached. Each arrangement is then picked with probability equal to
+	// the product of those, which telescopes to 1 / Arrangements().
+	pathCount := chain.pathCounts()
+
+	arrangement := []int{chain.Device()}
+	for current := chain.Device(); current != chain.Outlet(); {
+		pick := new(big.Int).Rand(rng, pathCount[current])
+
+		for _, source := range chain.reachableFrom[current] {
+			if pick.Cmp(pathCount[source]) < 0 {
+				current = source
+				break
+			}
+			pick.Sub(pick, pathCount[source])
+		}
+
+		arrangement = append(arrangement, current)
+	}
+
+	reverse(arrangement)
+	return arrangement
+}
+
+// Return an arrangement using as few adapters as possible, in the format
+// returned by `ArrangementIterator.Next`.
+func (chain *AdapterChain) Shortest() []int {
+	// Dynamic-programming approach, analogous to `pathCounts`: The
+	// shortest path to an adapter is one longer than the shortest path to
+	// any of the adapters from which it can be reached.
+	length := map[int]int{chain.Outlet(): 1}
+	predecessor := make(map[int]int)
+
+	for _, destination := range chain.ratings[1:] {
+		for _, source := range chain.reachableFrom[destination] {
+			if _, ok := length[destination]; !ok || length[source]+1 < length[destination] {
+				length[destination] = length[source] + 1
+				predecessor[destination] = source
+			}
+		}
+	}
+
+	arrangement := []int{chain.Device()}
+	for current := chain.Device(); current != chain.Outlet(); {
+		current = predecessor[current]
+		arrangement = append(arrangement, current)
+	}
+
+	reverse(arrangement)
+	return arrangement
+}
+
+// Return an arrangement using as many adapters as possible, in the format
+// returned by `ArrangementIterator.Next`.
+func (chain *AdapterChain) Longest() []int {
+	// As all adapters can be chained together - `newAdapterChain` makes
+	// sure of that - the longest arrangement is simply the one using all
+	// of them.
+	return append([]int{}, chain.ratings...)
+}
+
+func reverse(slice []int) {
+	for i, j := 0, len(slice)-1; i < j; i, j = i+1, j-1 {
+		slice[i], slice[j] = slice[j], slice[i]
+	}
+}
@@ -0,0 +1,105 @@
+package main
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Whether the arrangement is a valid way to connect outlet to device.
+func isValidArrangement(chain *AdapterChain, arrangement []int) bool {
+	if arrangement[0] != chain.Outlet() || arrangement[len(arrangement)-1] != chain.Device() {
+		return false
+	}
+
+	for i := 1; i < len(arrangement); i += 1 {
+		difference := arrangement[i] - arrangement[i-1]
+		if difference < 1 || difference > chain.maxGap {
+			return false
+		}
+	}
+
+	return true
+}
+
+func TestIterate(t *testing.T) {
+	chain, err := newAdapterChain(loadAdapters("adapters.input.test2"), defaultMaxGap)
+	assert.NoError(t, err)
+
+	seen := make(map[string]bool)
+	iterator := chain.Iterate()
+	for {
+		arrangement, ok := iterator.Next()
+		if !ok {
+			break
+		}
+
+		assert.True(t, isValidArrangement(chain, arrangement), "%v", arrangement)
+		seen[fmt.Sprint(arrangement)] = true
+	}
+
+	assert.Len(t, seen, 8)
+	assert.True(t, seen[fmt.Sprint([]int{0, 1, 4, 5, 6, 7, 10, 11, 12, 15, 16, 19, 22})])
+	assert.True(t, seen[fmt.Sprint([]int{0, 1, 4, 7, 10, 12, 15, 16, 19, 22})])
+
+	// Exhausted iterators stay exhausted
+	_, ok := iterator.Next()
+	assert.False(t, ok)
+}
+
+func TestIterateLazily(t *testing.T) {
+	// Far too many arrangements to enumerate, but the first few are
+	// available immediately.
+	var adapters []int
+	for i := 1; i <= 1000; i += 1 {
+		adapters = append(adapters, i)
+	}
+
+	chain, err := newAdapterChain(adapters, defaultMaxGap)
+	assert.NoError(t, err)
+
+	iterator := chain.Iterate()
+	for i := 0; i < 10; i += 1 {
+		arrangement, ok := iterator.Next()
+		assert.True(t, ok)
+		assert.True(t, isValidArrangement(chain, arrangement))
+	}
+}
+
+func TestSample(t *testing.T) {
+	chain, err := newAdapterChain(loadAdapters("adapters.input.test2"), defaultMaxGap)
+	assert.NoError(t, err)
+
+	rng := rand.New(rand.NewSource(1))
+	counts := make(map[string]int)
+	samples := 8000
+	for i := 0; i < samples; i += 1 {
+		arrangement := chain.Sample(rng)
+		assert.True(t, isValidArrangement(chain, arrangement), "%v", arrangement)
+		counts[fmt.Sprint(arrangement)] += 1
+	}
+
+	// Each of the 8 arrangements is expected 1000 times. Deviations of
+	// more than 15% are vanishingly unlikely for a uniform distribution.
+	assert.Len(t, counts, 8)
+	for arrangement, count := range counts {
+		assert.InDelta(t, 1000, count, 150, arrangement)
+	}
+}
+
+func TestShortestAndLongest(t *testing.T) {
+	chain, err := newAdapterChain(loadAdapters("adapters.input.test2"), defaultMaxGap)
+	assert.NoError(t, err)
+
+	assert.Equal(t, []int{0, 1, 4, 7, 10, 12, 15, 16, 19, 22}, chain.Shortest())
+	assert.Equal(t, []int{0, 1, 4, 5, 6, 7, 10, 11, 12, 15, 16, 19, 22}, chain.Longest())
+
+	{
+		chain, err := newAdapterChain([]int{1, 2, 3, 4, 5, 6}, defaultMaxGap)
+		assert.NoError(t, err)
+		assert.Len(t, chain.Shortest(), 4)
+		assert.True(t, isValidArrangement(chain, chain.Shortest()))
+	}
+}
@@ -0,0 +1,154 @@
+package main
+
+import (
+	"fmt"
+	"math/big"
+	"sort"
+)
+
+// Default maximum difference in joltage between two connected adapters.
+const defaultMaxGap int = 3
+
+// Chain of all adapters, connecting the charging outlet to our device.
+type AdapterChain struct {
+	// Joltage ratings in ascending order, including the outlet at 0 and
+	// the device at the end.
+	ratings []int
+
+	// Maximum difference in joltage between two connected adapters.
+	maxGap int
+
+	// For each rating, the ratings from which it can be reached.
+	// 5 => [2, 4] would eg mean that adapter 5 can be reached from
+	// adapters 2 and 4.
+	reachableFrom map[int][]int
+}
+
+// Build the chain of the given adapters, connecting adapters whose joltage
+// differs by at most `maxGap`. The device is assumed to be rated `maxGap`
+// higher than the highest adapter, the outlet is rated 0.
+//
+// Returns an error if the adapters can't all be chained together, if
+// multiple adapters have the same rating, or if any rating is not positive.
+func newAdapterChain(adapters []int, maxGap int) (*AdapterChain, error) {
+	if maxGap < 1 {
+		return nil, fmt.Errorf("Maximum gap must be positive: %d", maxGap)
+	}
+
+	for _, rating := range adapters {
+		// Adapters rated 0 or less would be indistinguishable from,
+		// or even precede, the outlet.
+		if rating < 1 {
+			return nil, fmt.Errorf("Adapter ratings must be positive: %d", rating)
+		}
+	}
+
+	chain := AdapterChain{maxGap: maxGap, reachableFrom: make(map[int][]int)}
+
+	// The charging outlet has an implicit Joltage rating of 0, which we
+	// simply add as a 'fake' adapter.
+	chain.ratings = append([]int{0}, adapters...)
+	sort.Ints(chain.ratings)
+
+	for i := 1; i < len(chain.ratings); i += 1 {
+		difference := chain.ratings[i] - chain.ratings[i-1]
+
+		if difference == 0 {
+			return nil, fmt.Errorf("Multiple adapters rated %d", chain.ratings[i])
+		} else if difference > maxGap {
+			return nil, fmt.Errorf(
+				"Unable to connect adapter %d to %d, difference exceeds %d",
+				chain.ratings[i-1], chain.ratings[i], maxGap,
+			)
+		}
+	}
+
+	// Our device is implicitly `maxGap` Jolts higher than the highest
+	// adapter.
+	device := chain.ratings[len(chain.ratings)-1] + maxGap
+	chain.ratings = append(chain.ratings, device)
+
+	for idx, destination := range chain.ratings {
+		for i := idx - 1; i >= 0; i -= 1 {
+			source := chain.ratings[i]
+			if source >= destination-maxGap {
+				// Destination can be reached from source
+				chain.reachableFrom[destination] = append(chain.reachableFrom[destination], source)
+			} else {
+				// As the list of adapters is sorted, no
+				// earlier adapter is going to be a valid
+				// source.
+				break
+			}
+		}
+	}
+
+	return &chain, nil
+}
+
+// Joltage rating of the outlet.
+func (chain *AdapterChain) Outlet() int {
+	return chain.ratings[0]
+}
+
+// Joltage rating of the device.
+func (chain *AdapterChain) Device() int {
+	return chain.ratings[len(chain.ratings)-1]
+}
+
+// Histogram of joltage differences when using all adapters, ie how often
+// each difference occurs between two consecutive adapters.
+func (chain *AdapterChain) Differences() map[int]int {
+	histogram := make(map[int]int)
+
+	for i := 0; i < len(chain.ratings)-1; i += 1 {
+		histogram[chain.ratings[i+1]-chain.ratings[i]] += 1
+	}
+
+	return histogram
+}
+
+// Number of distinct arrangements of adapters which connect the outlet to the
+// device. This grows exponentially with the number of adapters, hence the
+// arbitrary-precision integer.
+func (chain *AdapterChain) Arrangements() *big.Int {
+	return chain.pathCounts()[chain.Device()]
+}
+
+// Number of ways in which each adapter can be reached from the outlet.
+func (chain *AdapterChain) pathCounts() map[int]*big.Int {
+	// Dynamic-programming approach to calculate number of ways in which
+	// the final 'adapter' (ie the device) can be reached.
+	// Given a node `x` can be reached from nodes `a` and `b`, which can be
+	// reached in 3 and 5 ways respectively, then there node `x` can be
+	// reached in 3 + 5 = 8 ways.
+	//
+	// We pre-seed the source node, which can be reached in exactly 1 way
+	// (and has to be part of the path).
+	pathCount := make(map[int]*big.Int)
+	pathCount[chain.Outlet()] = big.NewInt(1)
+
+	for _, destination := range chain.ratings[1:] {
+		pathCount[destination] = big.NewInt(0)
+		for _, source := range chain.reachableFrom[destination] {
+			pathCount[destination].Add(pathCount[destination], pathCount[source])
+		}
+	}
+
+	return pathCount
+}
+
+// Adapters which are part of every arrangement, in ascending order.
+func (chain *AdapterChain) MandatoryAdapters() []int {
+	var mandatory []int
+
+	// An adapter can be skipped iff its neighbours can be connected
+	// directly. Outlet and device are not adapters, so are skipped.
+	for i := 1; i < len(chain.ratings)-1; i += 1 {
+		if chain.ratings[i+1]-chain.ratings[i-1] > chain.maxGap {
+			mandatory = append(mandatory, chain.ratings[i])
+		}
+	}
+
+	return mandatory
+}
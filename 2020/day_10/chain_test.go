@@ -0,0 +1,125 @@
+package main
+
+import (
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAdapterChainExamples(t *testing.T) {
+	examples := []struct {
+		path         string
+		ones         int
+		threes       int
+		arrangements int64
+	}{
+		{"adapters.input.test1", 22, 10, 19208},
+		{"adapters.input.test2", 7, 5, 8},
+	}
+
+	for _, example := range examples {
+		chain, err := newAdapterChain(loadAdapters(example.path), defaultMaxGap)
+		assert.NoError(t, err)
+
+		differences := chain.Differences()
+		assert.Equal(t, example.ones, differences[1], example.path)
+		assert.Equal(t, example.threes, differences[3], example.path)
+		assert.Equal(t, big.NewInt(example.arrangements), chain.Arrangements(), example.path)
+	}
+}
+
+func TestMandatoryAdapters(t *testing.T) {
+	// 0 (1) (4) 5 6 7 10 11 12 15 16 19 (22)
+	chain, err := newAdapterChain([]int{16, 10, 15, 5, 1, 11, 7, 19, 6, 12, 4}, defaultMaxGap)
+	assert.NoError(t, err)
+
+	assert.Equal(t, []int{1, 4, 7, 10, 12, 15, 16, 19}, chain.MandatoryAdapters())
+}
+
+func TestAdapterChainMaxGap(t *testing.T) {
+	adapters := []int{1, 2, 3, 4}
+
+	{
+		// Every adapter has to be used
+		chain, err := newAdapterChain(adapters, 1)
+		assert.NoError(t, err)
+		assert.Equal(t, 5, chain.Device())
+		assert.Equal(t, big.NewInt(1), chain.Arrangements())
+		assert.Equal(t, adapters, chain.MandatoryAdapters())
+		assert.Equal(t, map[int]int{1: 5}, chain.Differences())
+	}
+
+	{
+		// Fibonacci numbers
+		chain, err := newAdapterChain(adapters, 2)
+		assert.NoError(t, err)
+		assert.Equal(t, big.NewInt(5), chain.Arrangements())
+		assert.Equal(t, []int{4}, chain.MandatoryAdapters())
+	}
+
+	{
+		// Any subset of adapters works, as long as it contains the
+		// highest one, which is the only one connecting to the device.
+		chain, err := newAdapterChain(adapters, 4)
+		assert.NoError(t, err)
+		assert.Equal(t, big.NewInt(8), chain.Arrangements())
+		assert.Equal(t, []int{4}, chain.MandatoryAdapters())
+	}
+}
+
+func TestAdapterChainHugeInput(t *testing.T) {
+	// With a gap of 3 and no gaps between adapters, arrangements follow
+	// the Tribonacci numbers, which exceed 64 bits quickly.
+	var adapters []int
+	for i := 1; i <= 100; i += 1 {
+		adapters = append(adapters, i)
+	}
+
+	chain, err := newAdapterChain(adapters, defaultMaxGap)
+	assert.NoError(t, err)
+
+	expected, ok := new(big.Int).SetString("180396380815100901214157639", 10)
+	assert.True(t, ok)
+	assert.Equal(t, expected, chain.Arrangements())
+}
+
+func TestAdapterChainInvalid(t *testing.T) {
+	{
+		_, err := newAdapterChain([]int{1, 5}, defaultMaxGap)
+		assert.Error(t, err)
+	}
+
+	{
+		_, err := newAdapterChain([]int{1, 2, 2}, defaultMaxGap)
+		assert.Error(t, err)
+	}
+
+	{
+		_, err := newAdapterChain([]int{1, 2}, 0)
+		assert.Error(t, err)
+	}
+
+	{
+		_, err := newAdapterChain([]int{-1, 1}, defaultMaxGap)
+		assert.EqualError(t, err, "Adapter ratings must be positive: -1")
+	}
+
+	{
+		_, err := newAdapterChain([]int{0, 1}, defaultMaxGap)
+		assert.EqualError(t, err, "Adapter ratings must be positive: 0")
+	}
+}
+
+func TestLoadAdapters(t *testing.T) {
+	// The last rating is kept intact without a trailing newline
+	path := filepath.Join(t.TempDir(), "adapters.input")
+	assert.NoError(t, os.WriteFile(path, []byte("16\n10\n15"), 0644))
+	assert.Equal(t, []int{10, 15, 16}, loadAdapters(path))
+
+	assert.PanicsWithError(t, "open does-not-exist.input: no such file or directory", func() {
+		loadAdapters("does-not-exist.input")
+	})
+}
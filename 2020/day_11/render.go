@@ -0,0 +1,225 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"image"
+	"image/color"
+	"image/gif"
+	"image/png"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+var animate = flag.Bool("animate", false, "Animate simulations in the terminal")
+var frameDelay = flag.Duration("delay", 100*time.Millisecond, "Delay between frames of animations")
+var gifPrefix = flag.String("gif", "", "Write an animated GIF of each simulation to <prefix>_<task>.gif")
+var pngDir = flag.String("png", "", "Write PNG frames of each simulation to this directory")
+
+// Edge length, in pixels, of a single field in rendered images.
+const cellSize int = 4
+
+// ANSI escape sequences used for terminal animations.
+const (
+	ansiClearScreen = "\x1b[2J"
+	ansiCursorHome  = "\x1b[H"
+	ansiReset       = "\x1b[0m"
+	ansiRed         = "\x1b[31m"
+	ansiGreen       = "\x1b[32m"
+	ansiGrey        = "\x1b[90m"
+)
+
+// Colours of floor, empty and occupied fields in rendered images. The index of
+// a colour in the palette is returned by `paletteIndex`.
+var palette = color.Palette{
+	color.RGBA{0x30, 0x30, 0x30, 0xff},
+	color.RGBA{0x2e, 0xa0, 0x43, 0xff},
+	color.RGBA{0xd7, 0x3a, 0x31, 0xff},
+}
+
+func paletteIndex(field Field) uint8 {
+	switch {
+	case field.IsFloor():
+		return 0
+	case field.occupied:
+		return 2
+	default:
+		return 1
+	}
+}
+
+func ansiColour(field Field) string {
+	switch {
+	case field.IsFloor():
+		return ansiGrey
+	case field.occupied:
+		return ansiRed
+	default:
+		return ansiGreen
+	}
+}
+
+// Like `Board.String()`, but with fields coloured by means of ANSI escape
+// sequences.
+func (board *Board) AnsiString() string {
+	var out strings.Builder
+
+	for _, fields := range board.fields {
+		for _, field := range fields {
+			out.WriteString(ansiColour(field))
+			out.WriteString(field.String())
+		}
+		out.WriteString(ansiReset)
+		out.WriteString("\n")
+	}
+
+	return out.String()
+}
+
+// Render the board as an image, with each field being a square of `cellSize`
+// pixels.
+func (board *Board) Image() *image.Paletted {
+	bounds := image.Rect(0, 0, board.ColumnCount()*cellSize, board.RowCount()*cellSize)
+	img := image.NewPaletted(bounds, palette)
+
+	for row, fields := range board.fields {
+		for col, field := range fields {
+			index := paletteIndex(field)
+
+			for y := row * cellSize; y < (row+1)*cellSize; y += 1 {
+				for x := col * cellSize; x < (col+1)*cellSize; x += 1 {
+					img.SetColorIndex(x, y, index)
+				}
+			}
+		}
+	}
+
+	return img
+}
+
+// Renders the generations of a simulation to whichever outputs were requested
+// on the command line.
+type Renderer struct {
+	// Name of the simulation, used to name output files.
+	task string
+
+	// Terminal to animate the simulation on, nil if not animating.
+	terminal io.Writer
+
+	// Frames of the animated GIF, if one is to be written.
+	frames []*image.Paletted
+
+	// First error which occured while rendering. Observers can't return
+	// errors, so we keep it until the renderer is closed.
+	err error
+}
+
+func newRenderer(task string) *Renderer {
+	renderer := Renderer{task: task}
+	if *animate {
+		renderer.terminal = os.Stdout
+	}
+
+	return &renderer
+}
+
+// Observe a generation of the board. Suitable to be passed to
+// `runUntilStable`.
+func (renderer *Renderer) Observe(generation int, board *Board) {
+	if renderer.err != nil {
+		return
+	}
+
+	if renderer.terminal != nil {
+		if generation == 0 {
+			fmt.Fprint(renderer.terminal, ansiClearScreen)
+		}
+		// Moving the cursor home rather than clearing the screen
+		// redraws the board in place without flickering.
+		fmt.Fprint(renderer.terminal, ansiCursorHome)
+		fmt.Fprintf(renderer.terminal, "Task %s, generation %d\n", renderer.task, generation)
+		fmt.Fprint(renderer.terminal, board.AnsiString())
+
+		time.Sleep(*frameDelay)
+	}
+
+	if *gifPrefix == "" && *pngDir == "" {
+		return
+	}
+
+	img := board.Image()
+
+	if *gifPrefix != "" {
+		renderer.frames = append(renderer.frames, img)
+	}
+
+	if *pngDir != "" {
+		if generation == 0 {
+			if err := os.MkdirAll(*pngDir, 0o755); err != nil {
+				renderer.err = err
+				return
+			}
+		}
+
+		path := filepath.Join(*pngDir, fmt.Sprintf("%s_%04d.png", renderer.task, generation))
+		renderer.err = writePNG(path, img)
+	}
+}
+
+// Finish rendering, writing the animated GIF if one was requested.
+//
+// Returns the first error which occured while rendering, if any.
+func (renderer *Renderer) Close() error {
+	if renderer.err != nil {
+		return renderer.err
+	}
+
+	if *gifPrefix != "" && len(renderer.frames) > 0 {
+		path := fmt.Sprintf("%s_%s.gif", *gifPrefix, renderer.task)
+
+		file, err := os.Create(path)
+		if err != nil {
+			return err
+		}
+
+		if err := writeGIF(file, renderer.frames, *frameDelay); err != nil {
+			file.Close()
+			return err
+		}
+
+		return file.Close()
+	}
+
+	return nil
+}
+
+// Write an animated GIF of the given frames, looping forever.
+func writeGIF(w io.Writer, frames []*image.Paletted, delay time.Duration) error {
+	animation := gif.GIF{LoopCount: 0}
+
+	// GIF delays are specified in hundredths of a second
+	centiseconds := int(delay / (10 * time.Millisecond))
+	for _, frame := range frames {
+		animation.Image = append(animation.Image, frame)
+		animation.Delay = append(animation.Delay, centiseconds)
+	}
+
+	return gif.EncodeAll(w, &animation)
+}
+
+func writePNG(path string, img image.Image) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+
+	if err := png.Encode(file, img); err != nil {
+		file.Close()
+		return err
+	}
+
+	return file.Close()
+}
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"bytes"
+	"image"
+	"image/gif"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBoardImage(t *testing.T) {
+	board := Board{fields: [][]Field{
+		{{kind: "seat"}, {kind: "floor"}, {kind: "seat", occupied: true}},
+	}}
+
+	img := board.Image()
+	assert.Equal(t, 3*cellSize, img.Bounds().Dx())
+	assert.Equal(t, cellSize, img.Bounds().Dy())
+
+	assert.Equal(t, uint8(1), img.ColorIndexAt(0, 0))
+	assert.Equal(t, uint8(0), img.ColorIndexAt(cellSize, 0))
+	assert.Equal(t, uint8(2), img.ColorIndexAt(3*cellSize-1, cellSize-1))
+}
+
+func TestAnsiString(t *testing.T) {
+	board := Board{fields: [][]Field{
+		{{kind: "seat"}, {kind: "seat", occupied: true}},
+	}}
+
+	out := board.AnsiString()
+	assert.Equal(t, ansiGreen+"L"+ansiRed+"#"+ansiReset+"\n", out)
+}
+
+func TestWriteGIF(t *testing.T) {
+	board := Board{fields: [][]Field{
+		{{kind: "seat"}, {kind: "seat"}},
+	}}
+
+	first := board.Image()
+	board.Step(Parameters{FreeSeatThreshold: 4, GetNeighbours: getAdjacentNeighbours})
+	second := board.Image()
+
+	var buf bytes.Buffer
+	err := writeGIF(&buf, []*image.Paletted{first, second}, 250*time.Millisecond)
+	assert.NoError(t, err)
+
+	decoded, err := gif.DecodeAll(&buf)
+	assert.NoError(t, err)
+	assert.Len(t, decoded.Image, 2)
+	assert.Equal(t, []int{25, 25}, decoded.Delay)
+}
+
+func TestRendererWritesPNGFrames(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "frames", "nested")
+	previous := *pngDir
+	*pngDir = dir
+	defer func() { *pngDir = previous }()
+
+	board := Board{fields: [][]Field{
+		{{kind: "seat"}, {kind: "seat"}},
+	}}
+
+	renderer := newRenderer("test")
+	renderer.Observe(0, &board)
+	board.Step(Parameters{FreeSeatThreshold: 4, GetNeighbours: getAdjacentNeighbours})
+	renderer.Observe(1, &board)
+	assert.NoError(t, renderer.Close())
+
+	for _, name := range []string{"test_0000.png", "test_0001.png"} {
+		_, err := os.Stat(filepath.Join(dir, name))
+		assert.NoError(t, err)
+	}
+}
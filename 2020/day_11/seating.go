@@ -1,6 +1,7 @@
 package main
 
 import (
+	"flag"
 	"fmt"
 	"io/ioutil"
 	"strings"
@@ -9,6 +10,10 @@ import (
 const inputFile string = "seating.input"
 const debug bool = false
 
+// Upper bound on the number of generations `runUntilStable` will simulate
+// before giving up.
+const maxGenerations int = 10000
+
 type Field struct {
 	kind     string
 	occupied bool
@@ -147,6 +152,23 @@ func (board *Board) GetField(row int, col int) *Field {
 	}
 }
 
+// Returns the board's state as a string, suitable to detect previously seen
+// states. Unlike a hash, equal states are guaranteed to mean equal boards.
+func (board *Board) State() string {
+	var state strings.Builder
+
+	for _, fields := range board.fields {
+		for _, field := range fields {
+			state.WriteString(field.String())
+		}
+		// Row separator, so that boards of differing shape but
+		// identical contents don't collide.
+		state.WriteByte('\n')
+	}
+
+	return state.String()
+}
+
 func (board *Board) OccupiedSeatsCount() int {
 	count := 0
 
@@ -220,6 +242,8 @@ func (board *Board) Step(params Parameters) bool {
 }
 
 func main() {
+	flag.Parse()
+
 	taskOne()
 	taskTwo()
 }
@@ -235,7 +259,11 @@ func taskOne() {
 		GetNeighbours:       getAdjacentNeighbours,
 	}
 
-	runUntilStable(&board, params)
+	renderer := newRenderer("one")
+	result, err := runUntilStable(&board, params, maxGenerations, renderer.Observe)
+	check(err)
+	check(renderer.Close())
+	fmt.Println(result)
 
 	fmt.Printf("Occupied seat count of stable board: %d\n", board.OccupiedSeatsCount())
 }
@@ -251,23 +279,99 @@ func taskTwo() {
 		GetNeighbours:       getLineOfSightNeighbours,
 	}
 
-	runUntilStable(&board, params)
+	renderer := newRenderer("two")
+	result, err := runUntilStable(&board, params, maxGenerations, renderer.Observe)
+	check(err)
+	check(renderer.Close())
+	fmt.Println(result)
 
 	fmt.Printf("Occupied seat count of stable board: %d\n", board.OccupiedSeatsCount())
 }
 
-func runUntilStable(board *Board, params Parameters) {
-	for i := 0; ; i += 1 {
-		fmt.Printf("\nStep: %d\n", i)
-		fmt.Println(board)
-		boardChanged := board.Step(params)
-		if boardChanged {
-			fmt.Printf("Change observed\n")
-		} else {
-			fmt.Printf("No change observed => Board is stable\n")
-			break
+// Outcome of a simulation run by `runUntilStable`.
+type SimulationResult struct {
+	// Number of generations which were simulated.
+	Generations int
+
+	// Generation at which the board first entered the state it ended up
+	// cycling through.
+	CycleStart int
+
+	// Period of the cycle. A length of 1 indicates that the board
+	// settled, ie it no longer changes.
+	CycleLength int
+}
+
+// Whether the board settled into a state which no longer changes.
+func (result SimulationResult) IsStable() bool {
+	return result.CycleLength == 1
+}
+
+func (result SimulationResult) String() string {
+	if result.IsStable() {
+		return fmt.Sprintf("Board stable after %d generations", result.CycleStart)
+	} else {
+		return fmt.Sprintf(
+			"Board oscillates with period %d, starting at generation %d",
+			result.CycleLength, result.CycleStart,
+		)
+	}
+}
+
+// A function which is called with every generation of a simulated board,
+// including the initial one.
+type Observer func(generation int, board *Board)
+
+// Step the board until it either settles or starts to repeat itself.
+//
+// Every state the board passes through is recorded, so that a period-k cycle is
+// detected as soon as the board re-enters a state it was in before. In that
+// case the board is left in the first repeated state.
+//
+// If `observe` is not nil, it is called with each generation of the board.
+//
+// Returns an error if neither happens within `limit` generations.
+func runUntilStable(board *Board, params Parameters, limit int, observe Observer) (SimulationResult, error) {
+	// Generation at which each state was first seen
+	seen := make(map[string]int)
+	seen[board.State()] = 0
+
+	if observe != nil {
+		observe(0, board)
+	}
+
+	for generation := 1; generation <= limit; generation += 1 {
+		if debug {
+			fmt.Printf("\nGeneration: %d\n", generation-1)
+			fmt.Println(board)
+		}
+
+		if !board.Step(params) {
+			// Step did not change anything => Board is stable. The
+			// stable state was reached the generation before.
+			return SimulationResult{
+				Generations: generation,
+				CycleStart:  generation - 1,
+				CycleLength: 1,
+			}, nil
 		}
+
+		if observe != nil {
+			observe(generation, board)
+		}
+
+		state := board.State()
+		if start, ok := seen[state]; ok {
+			return SimulationResult{
+				Generations: generation,
+				CycleStart:  start,
+				CycleLength: generation - start,
+			}, nil
+		}
+		seen[state] = generation
 	}
+
+	return SimulationResult{Generations: limit}, fmt.Errorf("Board neither stable nor cyclic after %d generations", limit)
 }
 
 func check(e error) {
@@ -278,29 +382,54 @@ func check(e error) {
 
 func loadBoard() Board {
 	data, err := ioutil.ReadFile(inputFile)
-	// Remove trailing newline
-	data = data[:len(data)-1]
 	check(err)
 
+	board, err := parseBoard(string(data))
+	check(err)
+
+	return board
+}
+
+// Parse a board from its string representation, as returned by
+// `Board.String()`.
+//
+// Returns an error if the input contains invalid fields, or if its rows are of
+// differing length.
+func parseBoard(input string) (Board, error) {
 	var board Board
 
-	// ioutil.ReadFile returns a byte slice, strings.Split expects a string
-	for _, line := range strings.Split(string(data), "\n") {
+	// Remove trailing newline
+	input = strings.TrimSuffix(input, "\n")
+	if input == "" {
+		return board, nil
+	}
+
+	for idx, line := range strings.Split(input, "\n") {
 		var fields []Field
 
 		for _, thing := range line {
 			switch thing {
 			case 'L':
 				fields = append(fields, Field{kind: "seat"})
+			case '#':
+				fields = append(fields, Field{kind: "seat", occupied: true})
 			case '.':
 				fields = append(fields, Field{kind: "floor"})
 			default:
-				panic(fmt.Sprintf("Invalid input '%c' in line: %s\n", thing, line))
+				return board, fmt.Errorf("Invalid input '%c' in line %d: %s", thing, idx+1, line)
 			}
 		}
 
+		// `ColumnCount` relies on all rows being of equal length
+		if idx > 0 && len(fields) != board.ColumnCount() {
+			return board, fmt.Errorf(
+				"Line %d has %d fields, expected %d: %s",
+				idx+1, len(fields), board.ColumnCount(), line,
+			)
+		}
+
 		board.fields = append(board.fields, fields)
 	}
 
-	return board
+	return board, nil
 }
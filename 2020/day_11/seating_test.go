@@ -0,0 +1,178 @@
+package main
+
+import (
+	"io/ioutil"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRunUntilStable(t *testing.T) {
+	params := Parameters{
+		FreeSeatThreshold:   4,
+		OccupySeatThreshold: 0,
+		GetNeighbours:       getAdjacentNeighbours,
+	}
+
+	seat := Field{kind: "seat"}
+	floor := Field{kind: "floor"}
+	board := Board{fields: [][]Field{
+		{seat, floor, seat},
+	}}
+
+	// Both seats get occupied in the first step, and stay so.
+	result, err := runUntilStable(&board, params, 10, nil)
+	assert.NoError(t, err)
+	assert.True(t, result.IsStable())
+	assert.Equal(t, 1, result.CycleStart)
+	assert.Equal(t, 2, result.Generations)
+	assert.Equal(t, 2, board.OccupiedSeatsCount())
+}
+
+func TestRunUntilStableDetectsOscillation(t *testing.T) {
+	// Seats get freed as soon as a single neighbour is occupied, so two
+	// adjacent seats keep flipping between free and occupied.
+	params := Parameters{
+		FreeSeatThreshold:   1,
+		OccupySeatThreshold: 0,
+		GetNeighbours:       getAdjacentNeighbours,
+	}
+
+	seat := Field{kind: "seat"}
+	board := Board{fields: [][]Field{
+		{seat, seat},
+	}}
+
+	result, err := runUntilStable(&board, params, 10, nil)
+	assert.NoError(t, err)
+	assert.False(t, result.IsStable())
+	assert.Equal(t, 0, result.CycleStart)
+	assert.Equal(t, 2, result.CycleLength)
+	assert.Equal(t, 2, result.Generations)
+	assert.Equal(t, 0, board.OccupiedSeatsCount())
+}
+
+func TestRunUntilStableGenerationLimit(t *testing.T) {
+	params := Parameters{
+		FreeSeatThreshold:   1,
+		OccupySeatThreshold: 0,
+		GetNeighbours:       getAdjacentNeighbours,
+	}
+
+	seat := Field{kind: "seat"}
+	board := Board{fields: [][]Field{
+		{seat, seat},
+	}}
+
+	_, err := runUntilStable(&board, params, 1, nil)
+	assert.Error(t, err)
+}
+
+func TestParseBoard(t *testing.T) {
+	board, err := parseBoard("L.#\n#.L\n")
+	assert.NoError(t, err)
+	assert.Equal(t, 2, board.RowCount())
+	assert.Equal(t, 3, board.ColumnCount())
+	assert.Equal(t, 2, board.OccupiedSeatsCount())
+
+	assert.True(t, board.GetField(0, 0).IsSeat())
+	assert.False(t, board.GetField(0, 0).occupied)
+	assert.True(t, board.GetField(0, 1).IsFloor())
+	assert.True(t, board.GetField(0, 2).occupied)
+}
+
+func TestParseBoardInvalid(t *testing.T) {
+	{
+		_, err := parseBoard("L.L\nLxL\n")
+		assert.ErrorContains(t, err, "line 2")
+	}
+
+	{
+		_, err := parseBoard("L.L\nL.L\nL.\n")
+		assert.ErrorContains(t, err, "Line 3")
+	}
+
+	{
+		_, err := parseBoard("L.L\nL.L.\n")
+		assert.ErrorContains(t, err, "Line 2")
+	}
+}
+
+func TestBoardRoundTrip(t *testing.T) {
+	inputs := []string{
+		"L.LL.LL.LL\nLLLLLLL.LL\nL.L.L..L..\n",
+		"#.##.##.##\n#######.##\n#.#.#..#..\n",
+		"#.LL.L#.##\n#LLLLLL.L#\nL.L.L..L..\n",
+		"",
+	}
+
+	for _, input := range inputs {
+		board, err := parseBoard(input)
+		assert.NoError(t, err)
+		assert.Equal(t, input, board.String())
+	}
+
+	// Boards of intermediate simulation states survive a round-trip too
+	board, err := parseBoard(inputs[0])
+	assert.NoError(t, err)
+	params := Parameters{
+		FreeSeatThreshold:   4,
+		OccupySeatThreshold: 0,
+		GetNeighbours:       getAdjacentNeighbours,
+	}
+	for i := 0; i < 3; i += 1 {
+		board.Step(params)
+
+		parsed, err := parseBoard(board.String())
+		assert.NoError(t, err)
+		assert.Equal(t, board, parsed)
+	}
+}
+
+func TestExampleBoard(t *testing.T) {
+	data, err := ioutil.ReadFile("seating.input.test1")
+	assert.NoError(t, err)
+
+	{
+		board, err := parseBoard(string(data))
+		assert.NoError(t, err)
+
+		params := Parameters{
+			FreeSeatThreshold:   4,
+			OccupySeatThreshold: 0,
+			GetNeighbours:       getAdjacentNeighbours,
+		}
+		result, err := runUntilStable(&board, params, maxGenerations, nil)
+		assert.NoError(t, err)
+		assert.True(t, result.IsStable())
+		assert.Equal(t, 37, board.OccupiedSeatsCount())
+	}
+
+	{
+		board, err := parseBoard(string(data))
+		assert.NoError(t, err)
+
+		params := Parameters{
+			FreeSeatThreshold:   5,
+			OccupySeatThreshold: 0,
+			GetNeighbours:       getLineOfSightNeighbours,
+		}
+		result, err := runUntilStable(&board, params, maxGenerations, nil)
+		assert.NoError(t, err)
+		assert.True(t, result.IsStable())
+		assert.Equal(t, 26, board.OccupiedSeatsCount())
+	}
+}
+
+func TestBoardState(t *testing.T) {
+	a, err := parseBoard("L.#\n#.L\n")
+	assert.NoError(t, err)
+	b, err := parseBoard("L.#\n#.L\n")
+	assert.NoError(t, err)
+	assert.Equal(t, a.State(), b.State())
+
+	// Same contents, different shape
+	c, err := parseBoard("L.\n##\n.L\n")
+	assert.NoError(t, err)
+	assert.NotEqual(t, a.State(), c.State())
+}
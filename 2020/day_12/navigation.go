@@ -1,11 +1,15 @@
 package main
 
 import (
+	"flag"
 	"fmt"
 	"io/ioutil"
+	"os"
 	"regexp"
 	"strconv"
 	"strings"
+
+	"github.com/lavode/adventofcode/2020/pkg/numtheory"
 )
 
 const inputFile string = "navigation.input"
@@ -16,12 +20,16 @@ type Instruction struct {
 }
 
 type Ship struct {
-	// east-west (east positive)
-	longitude int
-	// north-south (north positive)
-	latitude int
+	position Vector
+
+	// Exact position of the ship, tracked once it moved at a heading which
+	// isn't a multiple of 90 degrees. `position` is then this rounded to
+	// the nearest integer coordinates.
+	exact   Point
+	offGrid bool
 
-	// Orientation. 0 = North, 90 = East, 180 = South, 270 = West
+	// Orientation in degrees, clockwise from north. 0 = North, 90 = East,
+	// 180 = South, 270 = West, though any other heading is valid too.
 	heading int
 
 	waypoint Waypoint
@@ -30,52 +38,52 @@ type Ship struct {
 // Waypoint coordinates are always relative to ship, ie really an offset in lat
 // / lon.
 type Waypoint struct {
-	// east-west (east positive)
-	longitude int
-	// north-south (north positive)
-	latitude int
-}
-
-func defaultWaypoint() Waypoint {
-	return Waypoint{longitude: 10, latitude: 1}
-}
+	offset Vector
 
-func (waypoint *Waypoint) MoveNorth(x int) {
-	waypoint.latitude += x
+	// Exact offset of the waypoint, tracked once it was rotated by an angle
+	// which isn't a multiple of 90 degrees. `offset` is then this rounded
+	// to the nearest integer coordinates.
+	exact   Point
+	offGrid bool
 }
 
-func (waypoint *Waypoint) MoveSouth(x int) {
-	waypoint.latitude -= x
+func defaultWaypoint() Waypoint {
+	return Waypoint{offset: Vector{X: 10, Y: 1}}
 }
 
-func (waypoint *Waypoint) MoveEast(x int) {
-	waypoint.longitude += x
-}
+// Move the waypoint `x` units into the given direction.
+func (waypoint *Waypoint) Move(direction Vector, x int) {
+	if waypoint.offGrid {
+		waypoint.exact = waypoint.exact.Add(direction.Point().Scale(float64(x)))
+		waypoint.offset = waypoint.exact.Round()
+		return
+	}
 
-func (waypoint *Waypoint) MoveWest(x int) {
-	waypoint.longitude -= x
+	waypoint.offset = waypoint.offset.Add(direction.Scale(x))
 }
 
 func (waypoint *Waypoint) RotateLeft(deg int) {
-	// Rotate left (ccw) deg / 90 times (integer division, will round down)
-	for i := 0; i < deg/90; i += 1 {
-		// Each ccw rotation by 90 degrees is equivalent to
-		// transforming (x, y) into (x', y') as:
-		// x' := -y
-		// y' := x
-		waypoint.longitude, waypoint.latitude = -waypoint.latitude, waypoint.longitude
+	if deg%90 == 0 && !waypoint.offGrid {
+		waypoint.offset = waypoint.offset.RotateLeft(deg)
+		return
 	}
+
+	waypoint.exact = waypoint.Exact().RotateLeft(deg)
+	waypoint.offset = waypoint.exact.Round()
+	waypoint.offGrid = true
 }
 
 func (waypoint *Waypoint) RotateRight(deg int) {
-	// Rotate right (cw) deg / 90 times (integer division, will round down)
-	for i := 0; i < deg/90; i += 1 {
-		// Each cw rotation by 90 degrees is equivalent to
-		// transforming (x, y) into (x', y') as:
-		// x' := y
-		// y' := -x
-		waypoint.longitude, waypoint.latitude = waypoint.latitude, -waypoint.longitude
+	waypoint.RotateLeft(-deg)
+}
+
+// Exact offset of the waypoint, without rounding.
+func (waypoint *Waypoint) Exact() Point {
+	if waypoint.offGrid {
+		return waypoint.exact
 	}
+
+	return waypoint.offset.Point()
 }
 
 func defaultShip() Ship {
@@ -87,13 +95,13 @@ func defaultShip() Ship {
 func ProcessDirectMovement(inst Instruction, ship *Ship) {
 	switch inst.command {
 	case "N":
-		ship.MoveNorth(inst.argument)
+		ship.Move(north, inst.argument)
 	case "S":
-		ship.MoveSouth(inst.argument)
+		ship.Move(south, inst.argument)
 	case "E":
-		ship.MoveEast(inst.argument)
+		ship.Move(east, inst.argument)
 	case "W":
-		ship.MoveWest(inst.argument)
+		ship.Move(west, inst.argument)
 	case "F":
 		ship.MoveForward(inst.argument)
 	case "L":
@@ -109,15 +117,15 @@ func ProcessDirectMovement(inst Instruction, ship *Ship) {
 func ProcessIndirectMovement(inst Instruction, ship *Ship) {
 	switch inst.command {
 	case "N":
-		ship.waypoint.MoveNorth(inst.argument)
+		ship.waypoint.Move(north, inst.argument)
 	case "S":
-		ship.waypoint.MoveSouth(inst.argument)
+		ship.waypoint.Move(south, inst.argument)
 	case "E":
-		ship.waypoint.MoveEast(inst.argument)
+		ship.waypoint.Move(east, inst.argument)
 	case "W":
-		ship.waypoint.MoveWest(inst.argument)
+		ship.waypoint.Move(west, inst.argument)
 	case "F":
-		ship.MoveToWaypoint(inst.argument)
+		check(ship.MoveToWaypoint(inst.argument))
 	case "L":
 		ship.waypoint.RotateLeft(inst.argument)
 	case "R":
@@ -127,113 +135,139 @@ func ProcessIndirectMovement(inst Instruction, ship *Ship) {
 	}
 }
 
-func (ship *Ship) MoveNorth(x int) {
-	ship.latitude += x
-}
+// Move the ship `x` units into the given direction, regardless of its heading.
+func (ship *Ship) Move(direction Vector, x int) {
+	if ship.offGrid {
+		ship.moveExact(direction.Point().Scale(float64(x)))
+		return
+	}
 
-func (ship *Ship) MoveSouth(x int) {
-	ship.latitude -= x
+	ship.position = ship.position.Add(direction.Scale(x))
 }
 
-func (ship *Ship) MoveEast(x int) {
-	ship.longitude += x
-}
+// Move the ship `x` units towards its current heading.
+//
+// For headings which aren't a multiple of 90 degrees, the ship's exact
+// position is tracked as well, and its position rounded to the nearest integer
+// coordinates after moving.
+func (ship *Ship) MoveForward(x int) {
+	// Headings are clockwise from north
+	if ship.heading%90 == 0 {
+		ship.Move(north.RotateRight(ship.heading), x)
+		return
+	}
 
-func (ship *Ship) MoveWest(x int) {
-	ship.longitude -= x
+	ship.moveExact(north.Point().Scale(float64(x)).RotateRight(ship.heading))
 }
 
-func (ship *Ship) MoveForward(x int) {
-	switch ship.heading {
-	case 0:
-		ship.MoveNorth(x)
-	case 90:
-		ship.MoveEast(x)
-	case 180:
-		ship.MoveSouth(x)
-	case 270:
-		ship.MoveWest(x)
-	default:
-		fmt.Printf("Unable to move towards current heading: %d\n", ship.heading)
+// Move the ship `x` times to the waypoint.
+//
+// Returns an error, leaving the ship where it was, if its position would
+// overflow. Once either the ship or the waypoint is off the integer grid, the
+// movement is calculated with floating point arithmetic instead, and never
+// fails.
+func (ship *Ship) MoveToWaypoint(x int) error {
+	if ship.offGrid || ship.waypoint.offGrid {
+		ship.moveExact(ship.waypoint.Exact().Scale(float64(x)))
+		return nil
 	}
-}
 
-func (ship *Ship) MoveToWaypoint(x int) {
 	// Waypoint is relative to ship, if ship moves so does the waypoint. As
 	// such we treat its lat/lon as offsets directly, and never change
 	// them.
+	// Moving to it `x` times is then simply moving by `x` times its
+	// offset.
+	offset, err := ship.waypoint.offset.CheckedScale(x)
+	if err != nil {
+		return err
+	}
+
+	position, err := ship.position.CheckedAdd(offset)
+	if err != nil {
+		return err
+	}
+
+	ship.position = position
+	return nil
+}
 
-	for i := 0; i < x; i += 1 {
-		ship.latitude += ship.waypoint.latitude
-		ship.longitude += ship.waypoint.longitude
+// Move the ship's exact position by `delta`, rounding its position to the
+// nearest integer coordinates.
+func (ship *Ship) moveExact(delta Point) {
+	if !ship.offGrid {
+		ship.exact = ship.position.Point()
+		ship.offGrid = true
 	}
+
+	ship.exact = ship.exact.Add(delta)
+	ship.position = ship.exact.Round()
 }
 
 func (ship *Ship) TurnLeft(x int) {
-	ship.heading = mod(ship.heading-x, 360)
+	ship.heading = numtheory.Mod(ship.heading-x, 360)
 }
 
 func (ship *Ship) TurnRight(x int) {
-	ship.heading = mod(ship.heading+x, 360)
+	ship.heading = numtheory.Mod(ship.heading+x, 360)
 }
 
 // Manhattan distance of ship from origin (0, 0)
 func (ship Ship) distance() int {
-	return abs(ship.longitude) + abs(ship.latitude)
+	return ship.position.Manhattan()
 }
 
-func abs(x int) int {
-	if x < 0 {
-		return -x
-	} else {
-		return x
-	}
-}
+var svgPath = flag.String("svg", "", "Plot the ships' tracks of both tasks as SVG to this file")
+var csvPrefix = flag.String("csv", "", "Write the ships' tracks as CSV to <prefix>_<model>.csv")
 
-// Go's built-in % operation is a remainder, not a mathematical modulus. As an
-// example, a % b < 0 if a < 0.
-// This is modulo as you know and love it from mathematics.
-func mod(a, b int) int {
-	m := a % b
+func main() {
+	flag.Parse()
 
-	if a < 0 {
-		m += abs(b)
-	}
+	direct := taskOne()
+	indirect := taskTwo()
 
-	return m
-}
+	if *svgPath != "" {
+		file, err := os.Create(*svgPath)
+		check(err)
+		check(writeSVG(file, direct, indirect))
+		check(file.Close())
+	}
 
-func main() {
-	taskOne()
-	taskTwo()
+	if *csvPrefix != "" {
+		for _, track := range []Track{direct, indirect} {
+			file, err := os.Create(fmt.Sprintf("%s_%s.csv", *csvPrefix, track.Name))
+			check(err)
+			check(track.WriteCSV(file))
+			check(file.Close())
+		}
+	}
 }
 
-func taskOne() {
+func taskOne() Track {
 	fmt.Println("== Task one ==")
 
-	instructions := loadInstructions()
+	instructions := loadInstructions(inputFile)
 	ship := defaultShip()
 
-	for _, instr := range instructions {
-		ProcessDirectMovement(instr, &ship)
-	}
+	track := recordTrack("direct", instructions, &ship, ProcessDirectMovement)
 
-	fmt.Printf("Ship's current position: Lat: %d, Lon: %d, Heading: %d\n", ship.latitude, ship.longitude, ship.heading)
+	fmt.Printf("Ship's current position: Lat: %d, Lon: %d, Heading: %d\n", ship.position.Y, ship.position.X, ship.heading)
 	fmt.Printf("Distance from origin: %d\n", ship.distance())
+
+	return track
 }
 
-func taskTwo() {
+func taskTwo() Track {
 	fmt.Println("== Task two ==")
 
-	instructions := loadInstructions()
+	instructions := loadInstructions(inputFile)
 	ship := defaultShip()
 
-	for _, instr := range instructions {
-		ProcessIndirectMovement(instr, &ship)
-	}
+	track := recordTrack("waypoint", instructions, &ship, ProcessIndirectMovement)
 
-	fmt.Printf("Ship's current position: Lat: %d, Lon: %d, Heading: %d\n", ship.latitude, ship.longitude, ship.heading)
+	fmt.Printf("Ship's current position: Lat: %d, Lon: %d, Heading: %d\n", ship.position.Y, ship.position.X, ship.heading)
 	fmt.Printf("Distance from origin: %d\n", ship.distance())
+
+	return track
 }
 
 func check(e error) {
@@ -242,10 +276,8 @@ func check(e error) {
 	}
 }
 
-func loadInstructions() []Instruction {
-	data, err := ioutil.ReadFile(inputFile)
-	// Remove trailing newline
-	data = data[:len(data)-1]
+func loadInstructions(path string) []Instruction {
+	data, err := ioutil.ReadFile(path)
 	check(err)
 
 	var instructions []Instruction
@@ -253,7 +285,7 @@ func loadInstructions() []Instruction {
 	matcher := regexp.MustCompile("^([A-Z])([0-9]+)$")
 
 	// ioutil.ReadFile returns a byte slice, strings.Split expects a string
-	for _, line := range strings.Split(string(data), "\n") {
+	for _, line := range strings.Split(strings.TrimSuffix(string(data), "\n"), "\n") {
 		match := matcher.FindStringSubmatch(line)
 		if match == nil || len(match) != 3 {
 			panic(fmt.Sprintf("Invalid input line: %s\n", line))
@@ -266,9 +298,9 @@ func loadInstructions() []Instruction {
 		switch instruction {
 		case "N", "S", "E", "W", "F":
 		case "L", "R":
-			if arg < 0 || arg > 360 || arg%90 != 0 {
-				panic(fmt.Sprintf("Invalid argument for turn left/right command: %d\n", arg))
-			}
+			// Turns by any angle are valid. Turns by multiples
+			// of 90 degrees are exact, all others are
+			// calculated with floating point arithmetic.
 		default:
 			panic(fmt.Sprintf("Invalid instruction '%s' in line %s\n", instruction, line))
 		}
@@ -0,0 +1,131 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/lavode/adventofcode/2020/pkg/numtheory"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExampleInstructions(t *testing.T) {
+	instructions := loadInstructions("navigation.input.test1")
+
+	{
+		ship := defaultShip()
+		for _, instr := range instructions {
+			ProcessDirectMovement(instr, &ship)
+		}
+
+		assert.Equal(t, Vector{X: 17, Y: -8}, ship.position)
+		assert.Equal(t, 25, ship.distance())
+	}
+
+	{
+		ship := defaultShip()
+		for _, instr := range instructions {
+			ProcessIndirectMovement(instr, &ship)
+		}
+
+		assert.Equal(t, Vector{X: 214, Y: -72}, ship.position)
+		assert.Equal(t, 286, ship.distance())
+	}
+}
+
+func TestMoveForwardArbitraryHeading(t *testing.T) {
+	ship := defaultShip()
+
+	// East => North-east
+	ship.TurnLeft(45)
+	assert.Equal(t, 45, ship.heading)
+	ship.MoveForward(141)
+	assert.Equal(t, Vector{X: 100, Y: 100}, ship.position)
+
+	// North-east => South
+	ship.TurnRight(135)
+	ship.MoveForward(100)
+	assert.Equal(t, Vector{X: 100, Y: 0}, ship.position)
+}
+
+func TestMoveForwardInSteps(t *testing.T) {
+	// Rounding after each step would move the ship by (1, 1) every time
+	whole := defaultShip()
+	whole.TurnLeft(45)
+	whole.MoveForward(141)
+
+	steps := defaultShip()
+	steps.TurnLeft(45)
+	for i := 0; i < 141; i += 1 {
+		steps.MoveForward(1)
+	}
+
+	assert.Equal(t, Vector{X: 100, Y: 100}, whole.position)
+	assert.Equal(t, whole.position, steps.position)
+	assert.InDelta(t, whole.exact.X, steps.exact.X, 1e-9)
+	assert.InDelta(t, whole.exact.Y, steps.exact.Y, 1e-9)
+}
+
+func TestWaypointArbitraryRotations(t *testing.T) {
+	ship := defaultShip()
+
+	// Eight turns by 45 degrees are a full turn, so the waypoint must end
+	// up where it started rather than drift.
+	for i := 0; i < 8; i += 1 {
+		ship.waypoint.RotateLeft(45)
+	}
+	assert.Equal(t, Vector{X: 10, Y: 1}, ship.waypoint.offset)
+
+	ship.waypoint.RotateRight(30)
+	assert.NoError(t, ship.MoveToWaypoint(1000))
+	expected := Vector{X: 10, Y: 1}.Point().RotateRight(30).Scale(1000).Round()
+	assert.Equal(t, expected, ship.position)
+}
+
+// Movement to the waypoint as it was implemented before, one step at a time.
+func moveToWaypointByLoop(ship *Ship, x int) {
+	for i := 0; i < x; i += 1 {
+		ship.position = ship.position.Add(ship.waypoint.offset)
+	}
+}
+
+func FuzzMoveToWaypoint(f *testing.F) {
+	f.Add(0, 0, 10, 1, 10)
+	f.Add(-17, 8, 4, -10, 72)
+	f.Add(5, 5, 0, 0, 1000)
+
+	f.Fuzz(func(t *testing.T, shipX int, shipY int, waypointX int, waypointY int, x int) {
+		// Keep inputs small, so the loop stays fast and nothing
+		// overflows.
+		shipX, shipY = shipX%1000000, shipY%1000000
+		waypointX, waypointY = waypointX%1000, waypointY%1000
+		x = numtheory.Mod(x, 10000)
+
+		expected := Ship{
+			position: Vector{X: shipX, Y: shipY},
+			waypoint: Waypoint{offset: Vector{X: waypointX, Y: waypointY}},
+		}
+		actual := expected
+
+		moveToWaypointByLoop(&expected, x)
+		assert.NoError(t, actual.MoveToWaypoint(x))
+		assert.Equal(t, expected, actual)
+	})
+}
+
+func TestMoveToWaypointOverflow(t *testing.T) {
+	ship := defaultShip()
+	ship.waypoint.offset = Vector{X: 1 << 40, Y: 1}
+
+	err := ship.MoveToWaypoint(1 << 30)
+	assert.Error(t, err)
+	assert.Equal(t, Vector{}, ship.position)
+
+	assert.NoError(t, ship.MoveToWaypoint(1<<20))
+	assert.Equal(t, Vector{X: 1 << 60, Y: 1 << 20}, ship.position)
+}
+
+func TestLoadInstructionsMissingFile(t *testing.T) {
+	// The read error itself is reported, not some slicing mishap
+	assert.PanicsWithError(t, "open does-not-exist.input: no such file or directory", func() {
+		loadInstructions("does-not-exist.input")
+	})
+}
@@ -0,0 +1,182 @@
+package main
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// State of the ship after processing an instruction.
+type TrackPoint struct {
+	// Instruction which was processed. Empty for the initial state.
+	Instruction Instruction
+
+	Position Vector
+	Heading  int
+	// Offset of the waypoint relative to the ship
+	Waypoint Vector
+}
+
+// Absolute position of the waypoint.
+func (point TrackPoint) WaypointPosition() Vector {
+	return point.Position.Add(point.Waypoint)
+}
+
+// Whether the point's instruction turned either ship or waypoint.
+func (point TrackPoint) IsTurn() bool {
+	return point.Instruction.command == "L" || point.Instruction.command == "R"
+}
+
+// The full track of a ship navigated by a sequence of instructions.
+type Track struct {
+	// Name of the movement model, eg "direct" or "waypoint"
+	Name string
+
+	// One point for the initial state, and one per processed instruction.
+	Points []TrackPoint
+}
+
+// Navigate the ship by the given instructions, using `process` to interpret
+// each of them, and record its track.
+func recordTrack(name string, instructions []Instruction, ship *Ship, process func(Instruction, *Ship)) Track {
+	track := Track{Name: name}
+	track.Points = append(track.Points, ship.trackPoint(Instruction{}))
+
+	for _, instr := range instructions {
+		process(instr, ship)
+		track.Points = append(track.Points, ship.trackPoint(instr))
+	}
+
+	return track
+}
+
+func (ship *Ship) trackPoint(instr Instruction) TrackPoint {
+	return TrackPoint{
+		Instruction: instr,
+		Position:    ship.position,
+		Heading:     ship.heading,
+		Waypoint:    ship.waypoint.offset,
+	}
+}
+
+// Write the track as CSV, with one row per track point.
+func (track Track) WriteCSV(w io.Writer) error {
+	writer := csv.NewWriter(w)
+
+	header := []string{"step", "instruction", "longitude", "latitude", "heading", "waypoint_longitude", "waypoint_latitude"}
+	if err := writer.Write(header); err != nil {
+		return err
+	}
+
+	for step, point := range track.Points {
+		instruction := ""
+		if point.Instruction.command != "" {
+			instruction = point.Instruction.command + strconv.Itoa(point.Instruction.argument)
+		}
+
+		record := []string{
+			strconv.Itoa(step),
+			instruction,
+			strconv.Itoa(point.Position.X),
+			strconv.Itoa(point.Position.Y),
+			strconv.Itoa(point.Heading),
+			strconv.Itoa(point.Waypoint.X),
+			strconv.Itoa(point.Waypoint.Y),
+		}
+		if err := writer.Write(record); err != nil {
+			return err
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+// Stroke colours of tracks in SVG plots, used in order.
+var trackColours = []string{"#1f77b4", "#d62728", "#2ca02c", "#9467bd"}
+
+// Plot the given tracks as SVG polylines into a shared coordinate system.
+//
+// Each track is plotted as the ship's path, along with a dashed path of the
+// waypoint's absolute position. In the direct model the waypoint is merely
+// carried along, so its path is the ship's shifted by a constant offset.
+//
+// The origin is marked with a cross, the position of each turn with a circle.
+// North points up.
+func writeSVG(w io.Writer, tracks ...Track) error {
+	// Bounding box of all tracks, including the origin
+	var lower, upper Vector
+	for _, track := range tracks {
+		for _, point := range track.Points {
+			for _, position := range []Vector{point.Position, point.WaypointPosition()} {
+				lower.X, lower.Y = min(lower.X, position.X), min(lower.Y, position.Y)
+				upper.X, upper.Y = max(upper.X, position.X), max(upper.Y, position.Y)
+			}
+		}
+	}
+
+	size := max(upper.X-lower.X, upper.Y-lower.Y, 1)
+	// Keep line widths and markers at a constant fraction of the plot
+	// size, irrespective of the input's scale.
+	unit := float64(size) / 500
+	margin := int(20*unit) + 1
+
+	var out strings.Builder
+
+	// SVG's y axis points down, ours north. As such we plot (x, -y).
+	fmt.Fprintf(
+		&out,
+		"<svg xmlns=\"http://www.w3.org/2000/svg\" viewBox=\"%d %d %d %d\">\n",
+		lower.X-margin, -upper.Y-margin, upper.X-lower.X+2*margin, upper.Y-lower.Y+2*margin,
+	)
+
+	fmt.Fprintf(
+		&out,
+		"  <path d=\"M %g %g L %g %g M %g %g L %g %g\" stroke=\"black\" stroke-width=\"%g\"/>\n",
+		-5*unit, -5*unit, 5*unit, 5*unit, -5*unit, 5*unit, 5*unit, -5*unit, unit,
+	)
+
+	for idx, track := range tracks {
+		colour := trackColours[idx%len(trackColours)]
+
+		fmt.Fprintf(&out, "  <g id=\"%s\" stroke=\"%s\">\n", track.Name, colour)
+		fmt.Fprintf(&out, "    <title>%s</title>\n", track.Name)
+
+		var points, waypoints []string
+		for _, point := range track.Points {
+			points = append(points, fmt.Sprintf("%d,%d", point.Position.X, -point.Position.Y))
+
+			waypoint := point.WaypointPosition()
+			waypoints = append(waypoints, fmt.Sprintf("%d,%d", waypoint.X, -waypoint.Y))
+		}
+		fmt.Fprintf(
+			&out,
+			"    <polyline points=\"%s\" fill=\"none\" stroke-width=\"%g\"/>\n",
+			strings.Join(points, " "), unit,
+		)
+		fmt.Fprintf(
+			&out,
+			"    <polyline class=\"waypoint\" points=\"%s\" fill=\"none\" stroke-width=\"%g\" stroke-dasharray=\"%g\" stroke-opacity=\"0.6\"/>\n",
+			strings.Join(waypoints, " "), unit, 4*unit,
+		)
+
+		for _, point := range track.Points {
+			if point.IsTurn() {
+				fmt.Fprintf(
+					&out,
+					"    <circle cx=\"%d\" cy=\"%d\" r=\"%g\" fill=\"%s\"/>\n",
+					point.Position.X, -point.Position.Y, 2*unit, colour,
+				)
+			}
+		}
+
+		fmt.Fprintf(&out, "  </g>\n")
+	}
+
+	fmt.Fprintf(&out, "</svg>\n")
+
+	_, err := io.WriteString(w, out.String())
+	return err
+}
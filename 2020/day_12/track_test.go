@@ -0,0 +1,76 @@
+package main
+
+import (
+	"bytes"
+	"encoding/xml"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRecordTrack(t *testing.T) {
+	instructions := loadInstructions("navigation.input.test1")
+	ship := defaultShip()
+
+	track := recordTrack("waypoint", instructions, &ship, ProcessIndirectMovement)
+	assert.Len(t, track.Points, len(instructions)+1)
+
+	assert.Equal(t, Vector{}, track.Points[0].Position)
+	assert.Equal(t, Vector{X: 10, Y: 1}, track.Points[0].Waypoint)
+
+	// F10
+	assert.Equal(t, Vector{X: 100, Y: 10}, track.Points[1].Position)
+	// R90
+	assert.True(t, track.Points[4].IsTurn())
+	assert.Equal(t, Vector{X: 4, Y: -10}, track.Points[4].Waypoint)
+
+	assert.Equal(t, ship.position, track.Points[len(track.Points)-1].Position)
+}
+
+func TestTrackCSV(t *testing.T) {
+	ship := defaultShip()
+	track := recordTrack("direct", []Instruction{{command: "F", argument: 10}}, &ship, ProcessDirectMovement)
+
+	var buf bytes.Buffer
+	assert.NoError(t, track.WriteCSV(&buf))
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	assert.Equal(t, []string{
+		"step,instruction,longitude,latitude,heading,waypoint_longitude,waypoint_latitude",
+		"0,,0,0,90,10,1",
+		"1,F10,10,0,90,10,1",
+	}, lines)
+}
+
+func TestWriteSVG(t *testing.T) {
+	instructions := loadInstructions("navigation.input.test1")
+
+	directShip, indirectShip := defaultShip(), defaultShip()
+	direct := recordTrack("direct", instructions, &directShip, ProcessDirectMovement)
+	indirect := recordTrack("waypoint", instructions, &indirectShip, ProcessIndirectMovement)
+
+	var buf bytes.Buffer
+	assert.NoError(t, writeSVG(&buf, direct, indirect))
+
+	svg := buf.String()
+	// Ship and waypoint per track
+	assert.Equal(t, 4, strings.Count(svg, "<polyline"))
+	assert.Equal(t, 2, strings.Count(svg, `class="waypoint"`))
+	// One R90 per track
+	assert.Equal(t, 2, strings.Count(svg, "<circle"))
+	// North is plotted upwards
+	assert.Contains(t, svg, "100,-10")
+	// Waypoint of the waypoint model after F10, at ship plus offset
+	assert.Contains(t, svg, "110,-11")
+
+	// Output is well-formed XML
+	decoder := xml.NewDecoder(&buf)
+	for {
+		_, err := decoder.Token()
+		if err != nil {
+			assert.Equal(t, "EOF", err.Error())
+			break
+		}
+	}
+}
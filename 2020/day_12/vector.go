@@ -0,0 +1,215 @@
+package main
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/lavode/adventofcode/2020/pkg/numtheory"
+)
+
+// A vector in the plane.
+type Vector struct {
+	// east-west (east positive)
+	X int
+	// north-south (north positive)
+	Y int
+}
+
+// Unit vectors of the four cardinal directions.
+var (
+	north = Vector{X: 0, Y: 1}
+	east  = Vector{X: 1, Y: 0}
+	south = Vector{X: 0, Y: -1}
+	west  = Vector{X: -1, Y: 0}
+)
+
+func (v Vector) String() string {
+	return fmt.Sprintf("(%d, %d)", v.X, v.Y)
+}
+
+// Add two vectors. Panics if the result overflows, see `CheckedAdd` for a
+// non-panicking version.
+func (v Vector) Add(w Vector) Vector {
+	out, err := v.CheckedAdd(w)
+	check(err)
+
+	return out
+}
+
+// Multiply the vector by a scalar. Panics if the result overflows, see
+// `CheckedScale` for a non-panicking version.
+func (v Vector) Scale(k int) Vector {
+	out, err := v.CheckedScale(k)
+	check(err)
+
+	return out
+}
+
+// Add two vectors, returning an error if the result overflows.
+func (v Vector) CheckedAdd(w Vector) (Vector, error) {
+	x, err := checkedAdd(v.X, w.X)
+	if err != nil {
+		return v, err
+	}
+
+	y, err := checkedAdd(v.Y, w.Y)
+	if err != nil {
+		return v, err
+	}
+
+	return Vector{X: x, Y: y}, nil
+}
+
+// Multiply the vector by a scalar, returning an error if the result overflows.
+func (v Vector) CheckedScale(k int) (Vector, error) {
+	x, err := checkedMul(v.X, k)
+	if err != nil {
+		return v, err
+	}
+
+	y, err := checkedMul(v.Y, k)
+	if err != nil {
+		return v, err
+	}
+
+	return Vector{X: x, Y: y}, nil
+}
+
+// Manhattan distance of the vector's tip from the origin.
+func (v Vector) Manhattan() int {
+	return numtheory.Abs(v.X) + numtheory.Abs(v.Y)
+}
+
+// Rotate the vector counter-clockwise by `deg` degrees.
+//
+// Rotations by multiples of 90 degrees are exact. Any other rotation is
+// calculated with floating point arithmetic, with the resulting coordinates
+// being rounded to the nearest integer. Use `Point` to rotate repeatedly
+// without accumulating rounding errors.
+func (v Vector) RotateLeft(deg int) Vector {
+	if deg%90 == 0 {
+		// Each ccw rotation by 90 degrees is equivalent to
+		// transforming (x, y) into (x', y') as:
+		// x' := -y
+		// y' := x
+		// Four of those are a full turn, so only the remainder of the
+		// number of quarter turns matters.
+		switch numtheory.Mod(deg/90, 4) {
+		case 1:
+			return Vector{X: -v.Y, Y: v.X}
+		case 2:
+			return Vector{X: -v.X, Y: -v.Y}
+		case 3:
+			return Vector{X: v.Y, Y: -v.X}
+		default:
+			return v
+		}
+	}
+
+	return v.Point().RotateLeft(deg).Round()
+}
+
+// Rotate the vector clockwise by `deg` degrees.
+//
+// See `RotateLeft` for details on precision.
+func (v Vector) RotateRight(deg int) Vector {
+	return v.RotateLeft(-deg)
+}
+
+// Convert the vector to a point with the same coordinates.
+func (v Vector) Point() Point {
+	return Point{X: float64(v.X), Y: float64(v.Y)}
+}
+
+// A vector in the plane with floating point coordinates.
+//
+// Used to keep track of positions which are moved or rotated by angles other
+// than multiples of 90 degrees, as rounding those to integer coordinates after
+// each step would accumulate errors.
+type Point struct {
+	// east-west (east positive)
+	X float64
+	// north-south (north positive)
+	Y float64
+}
+
+func (p Point) String() string {
+	return fmt.Sprintf("(%g, %g)", p.X, p.Y)
+}
+
+func (p Point) Add(q Point) Point {
+	return Point{X: p.X + q.X, Y: p.Y + q.Y}
+}
+
+func (p Point) Scale(k float64) Point {
+	return Point{X: p.X * k, Y: p.Y * k}
+}
+
+// Rotate the point counter-clockwise around the origin by `deg` degrees.
+func (p Point) RotateLeft(deg int) Point {
+	sin, cos := sincos(deg)
+
+	return Point{
+		X: p.X*cos - p.Y*sin,
+		Y: p.X*sin + p.Y*cos,
+	}
+}
+
+// Rotate the point clockwise around the origin by `deg` degrees.
+func (p Point) RotateRight(deg int) Point {
+	return p.RotateLeft(-deg)
+}
+
+// Round the point to the nearest integer coordinates.
+func (p Point) Round() Vector {
+	return Vector{X: int(math.Round(p.X)), Y: int(math.Round(p.Y))}
+}
+
+// Sine and cosine of an angle given in degrees. Exact for multiples of 90
+// degrees, which `math.Sincos` isn't due to pi not being representable.
+func sincos(deg int) (float64, float64) {
+	if deg%90 == 0 {
+		switch numtheory.Mod(deg/90, 4) {
+		case 1:
+			return 1, 0
+		case 2:
+			return 0, -1
+		case 3:
+			return -1, 0
+		default:
+			return 0, 1
+		}
+	}
+
+	return math.Sincos(float64(deg) * math.Pi / 180)
+}
+
+// Returns a + b, or an error if the sum overflows.
+func checkedAdd(a, b int) (int, error) {
+	sum := a + b
+
+	// Overflow happens iff both summands have the same sign, and the sum
+	// has a different one.
+	if (a >= 0) == (b >= 0) && (sum >= 0) != (a >= 0) {
+		return 0, fmt.Errorf("Integer overflow: %d + %d", a, b)
+	}
+
+	return sum, nil
+}
+
+// Returns a * b, or an error if the product overflows.
+func checkedMul(a, b int) (int, error) {
+	if a == 0 || b == 0 {
+		return 0, nil
+	}
+
+	product := a * b
+
+	// The only product whose overflow isn't caught by dividing it back
+	// is -1 * MinInt, as MinInt / -1 overflows itself.
+	if product/b != a || (a == -1 && b == math.MinInt) || (b == -1 && a == math.MinInt) {
+		return 0, fmt.Errorf("Integer overflow: %d * %d", a, b)
+	}
+
+	return product, nil
+}
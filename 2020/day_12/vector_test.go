@@ -0,0 +1,98 @@
+package main
+
+import (
+	"math"
+	"testing"
+
+	"github.com/lavode/adventofcode/2020/pkg/numtheory"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRotateQuarterTurns(t *testing.T) {
+	v := Vector{X: 10, Y: 4}
+
+	assert.Equal(t, Vector{X: -4, Y: 10}, v.RotateLeft(90))
+	assert.Equal(t, Vector{X: -10, Y: -4}, v.RotateLeft(180))
+	assert.Equal(t, Vector{X: 4, Y: -10}, v.RotateLeft(270))
+	assert.Equal(t, v, v.RotateLeft(360))
+	assert.Equal(t, v, v.RotateLeft(0))
+
+	assert.Equal(t, Vector{X: 4, Y: -10}, v.RotateRight(90))
+	assert.Equal(t, v.RotateLeft(90), v.RotateRight(270))
+	assert.Equal(t, v.RotateLeft(90), v.RotateRight(-90))
+	assert.Equal(t, v.RotateLeft(90), v.RotateLeft(450))
+}
+
+func TestRotateArbitraryAngles(t *testing.T) {
+	// sqrt(2) * 100 * (cos 45°, sin 45°) = (100, 100)
+	assert.Equal(t, Vector{X: 100, Y: 100}, Vector{X: 141, Y: 0}.RotateLeft(45))
+	assert.Equal(t, Vector{X: 100, Y: -100}, Vector{X: 141, Y: 0}.RotateRight(45))
+
+	// (cos 30°, sin 30°) * 1000 = (866.03, 500)
+	assert.Equal(t, Vector{X: 866, Y: 500}, Vector{X: 1000, Y: 0}.RotateLeft(30))
+}
+
+func TestManhattan(t *testing.T) {
+	assert.Equal(t, 0, Vector{}.Manhattan())
+	assert.Equal(t, 25, Vector{X: 17, Y: -8}.Manhattan())
+}
+
+func TestCheckedArithmetic(t *testing.T) {
+	{
+		_, err := Vector{X: math.MaxInt, Y: 0}.CheckedAdd(Vector{X: 1, Y: 0})
+		assert.Error(t, err)
+	}
+
+	{
+		_, err := Vector{X: 0, Y: math.MinInt}.CheckedAdd(Vector{X: 0, Y: -1})
+		assert.Error(t, err)
+	}
+
+	{
+		v, err := Vector{X: math.MaxInt, Y: math.MinInt}.CheckedAdd(Vector{X: math.MinInt, Y: math.MaxInt})
+		assert.NoError(t, err)
+		assert.Equal(t, Vector{X: -1, Y: -1}, v)
+	}
+
+	{
+		_, err := Vector{X: 1 << 62, Y: 0}.CheckedScale(2)
+		assert.Error(t, err)
+	}
+
+	{
+		_, err := Vector{X: math.MinInt, Y: 0}.CheckedScale(-1)
+		assert.Error(t, err)
+	}
+
+	{
+		v, err := Vector{X: 1 << 61, Y: -3}.CheckedScale(-2)
+		assert.NoError(t, err)
+		assert.Equal(t, Vector{X: -(1 << 62), Y: 6}, v)
+	}
+}
+
+// Rotation as it was implemented before, one quarter turn at a time.
+func rotateLeftByLoop(v Vector, deg int) Vector {
+	for i := 0; i < deg/90; i += 1 {
+		v.X, v.Y = -v.Y, v.X
+	}
+
+	return v
+}
+
+func FuzzRotateLeft(f *testing.F) {
+	f.Add(10, 4, 90)
+	f.Add(-3, 7, 270)
+	f.Add(0, 0, 3600)
+
+	f.Fuzz(func(t *testing.T, x int, y int, quarterTurns int) {
+		// Keep inputs small, so the loop stays fast and nothing
+		// overflows.
+		x, y = x%1000000, y%1000000
+		deg := numtheory.Mod(quarterTurns, 100) * 90
+
+		v := Vector{X: x, Y: y}
+		assert.Equal(t, rotateLeftByLoop(v, deg), v.RotateLeft(deg))
+		assert.Equal(t, rotateLeftByLoop(v, 360*100-deg), v.RotateRight(deg))
+	})
+}
@@ -3,8 +3,11 @@ package main
 import (
 	"fmt"
 	"io/ioutil"
+	"math/big"
 	"strconv"
 	"strings"
+
+	"github.com/lavode/adventofcode/2020/pkg/numtheory"
 )
 
 const inputFile string = "bus.input"
@@ -20,27 +23,41 @@ type Bus struct {
 	inService bool
 }
 
-func (bus Bus) NextStart(now int) int {
+// Returns the time of the bus' next start after `now`. If `inclusive` is set,
+// a bus starting exactly at `now` counts as its next start.
+//
+// Returns an error if the bus has no valid - ie positive - roundtrip time.
+func (bus Bus) NextStart(now int, inclusive bool) (int, error) {
+	if bus.id < 1 {
+		return 0, fmt.Errorf("Bus %d has no valid roundtrip time", bus.id)
+	}
+
 	// As every bus started at 0, with roundtripe-time `id`, starts will be
 	// at `k * id` for integer values of k.
 
-	leftSecondsAgo := now % bus.id
+	leftSecondsAgo := numtheory.Mod(now, bus.id)
+	if leftSecondsAgo == 0 && inclusive {
+		return now, nil
+	}
+
 	nextStartIn := bus.id - leftSecondsAgo
 
-	return now + nextStartIn
+	return now + nextStartIn, nil
 }
 
 func taskOne() {
 	fmt.Println("== Task one ==")
 
-	now, buses := loadBuses()
+	now, buses := loadBuses(inputFile)
 	nextStart := make(map[int]int)
 
 	fmt.Println("Current TS = ", now)
 	// Each bus' numerical ID is equal to its roundtrip time.
 	for _, bus := range buses {
 		if bus.inService {
-			start := bus.NextStart(now)
+			// A bus leaving right now can still be caught
+			start, err := bus.NextStart(now, true)
+			check(err)
 			fmt.Printf("Bus %d: Next start at %d\n", bus.id, start)
 			nextStart[bus.id] = start
 		}
@@ -62,23 +79,41 @@ func taskOne() {
 func taskTwo() {
 	fmt.Println("== Task two ==")
 
-	_, buses := loadBuses()
+	_, buses := loadBuses(inputFile)
 
-	fmt.Println("Solve the following set of linear equations, for integer solutions of `k_i`:")
+	fmt.Println("Solving the following congruence equation system: (Notation: Take == to mean the equivalence relation)")
 	for idx, bus := range buses {
 		if bus.inService {
-			fmt.Printf("t + %d = k_%d * %d\n", idx, idx, bus.id)
+			fmt.Printf("t == -%d mod %d\n", idx, bus.id)
 		}
 	}
 
-	fmt.Println("Or equivalently the following congruence equation system: (Notation: Take == to mean the equivalence relation)")
+	timestamp, err := earliestConsecutiveDepartures(buses)
+	check(err)
+
+	fmt.Printf("Earliest timestamp: %s\n", timestamp)
+}
+
+// Find the earliest timestamp `t` such that the bus at position `i` of the
+// list departs at `t + i`. Buses which aren't in service impose no
+// restriction.
+//
+// Returns an error if there is no such timestamp.
+func earliestConsecutiveDepartures(buses []Bus) (*big.Int, error) {
+	// Bus at index i departs at t + i iff t + i == 0 mod id, ie
+	// t == -i mod id.
+	var congruences []numtheory.Congruence
 	for idx, bus := range buses {
 		if bus.inService {
-			fmt.Printf("t == -%d mod %d\n", idx, bus.id)
+			congruences = append(congruences, numtheory.Congruence{
+				Remainder: big.NewInt(int64(-idx)),
+				Modulus:   big.NewInt(int64(bus.id)),
+			})
 		}
 	}
 
-	fmt.Println("As the bus IDs are pairwise coprime, you can easily solve this with the CRT")
+	t, _, err := numtheory.CRTBig(congruences)
+	return t, err
 }
 
 func check(e error) {
@@ -87,17 +122,13 @@ func check(e error) {
 	}
 }
 
-func loadBuses() (timestamp int, busIDs []Bus) {
-	data, err := ioutil.ReadFile(inputFile)
-	// Remove trailing newline
-	data = data[:len(data)-1]
+func loadBuses(path string) (timestamp int, busIDs []Bus) {
+	data, err := ioutil.ReadFile(path)
 	check(err)
 
-	var buses []Bus
-
 	// First line is current timestamp
 	// Second line is Travel times / IDs of bus lines
-	lines := strings.Split(string(data), "\n")
+	lines := strings.Split(strings.TrimSuffix(string(data), "\n"), "\n")
 	if len(lines) != 2 {
 		panic(fmt.Sprintf("Input has invalid number of lines: %d\n", len(lines)))
 	}
@@ -105,19 +136,38 @@ func loadBuses() (timestamp int, busIDs []Bus) {
 	ts, err := strconv.Atoi(lines[0])
 	check(err)
 
-	for _, busID := range strings.Split(lines[1], ",") {
+	buses, err := parseBuses(lines[1])
+	check(err)
+
+	return ts, buses
+}
+
+// Parse a comma-separated list of bus IDs, with 'x' denoting buses which
+// aren't in service.
+//
+// Returns an error if an ID is not a positive integer, as it is also the bus'
+// roundtrip time.
+func parseBuses(line string) ([]Bus, error) {
+	var buses []Bus
+
+	for idx, busID := range strings.Split(line, ",") {
 		bus := Bus{inService: true}
 
 		if busID == "x" {
 			bus.inService = false
 		} else {
 			id, err := strconv.Atoi(busID)
-			check(err)
+			if err != nil {
+				return nil, fmt.Errorf("Bus %d: %v", idx+1, err)
+			}
+			if id < 1 {
+				return nil, fmt.Errorf("Bus %d: ID must be positive, got %d", idx+1, id)
+			}
 			bus.id = id
 		}
 
 		buses = append(buses, bus)
 	}
 
-	return ts, buses
+	return buses, nil
 }
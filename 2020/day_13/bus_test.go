@@ -0,0 +1,65 @@
+package main
+
+import (
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEarliestConsecutiveDepartures(t *testing.T) {
+	examples := map[string]int64{
+		"7,13,x,x,59,x,31,19": 1068781,
+		"17,x,13,19":          3417,
+		"67,7,59,61":          754018,
+		"67,x,7,59,61":        779210,
+		"67,7,x,59,61":        1261476,
+		"1789,37,47,1889":     1202161486,
+	}
+
+	for line, expected := range examples {
+		timestamp, err := earliestConsecutiveDepartures(mustParseBuses(t, line))
+		assert.NoError(t, err)
+		assert.Equal(t, big.NewInt(expected), timestamp, line)
+	}
+}
+
+func TestEarliestConsecutiveDeparturesInconsistent(t *testing.T) {
+	// Bus 4 departs at even timestamps only, so does bus 6.
+	_, err := earliestConsecutiveDepartures(mustParseBuses(t, "4,6"))
+	assert.Error(t, err)
+}
+
+func mustParseBuses(t *testing.T, line string) []Bus {
+	buses, err := parseBuses(line)
+	assert.NoError(t, err)
+
+	return buses
+}
+
+func TestParseBusesInvalid(t *testing.T) {
+	for _, line := range []string{"7,0,13", "7,x,-5", "7,y", "7,,13"} {
+		_, err := parseBuses(line)
+		assert.Error(t, err, line)
+	}
+
+	_, err := parseBuses("7,x,-5")
+	assert.EqualError(t, err, "Bus 3: ID must be positive, got -5")
+}
+
+func TestLoadBuses(t *testing.T) {
+	// The last bus ID is kept intact without a trailing newline
+	path := filepath.Join(t.TempDir(), "bus.input")
+	assert.NoError(t, os.WriteFile(path, []byte("939\n7,13,x,x,59,x,31,19"), 0644))
+
+	now, buses := loadBuses(path)
+	assert.Equal(t, 939, now)
+	assert.Len(t, buses, 8)
+	assert.Equal(t, 19, buses[7].id)
+
+	assert.PanicsWithError(t, "open does-not-exist.input: no such file or directory", func() {
+		loadBuses("does-not-exist.input")
+	})
+}
@@ -0,0 +1,230 @@
+package main
+
+import (
+	"container/heap"
+	"fmt"
+
+	"github.com/lavode/adventofcode/2020/pkg/numtheory"
+)
+
+// A single departure of a bus.
+type Departure struct {
+	Time int
+	Bus  int
+}
+
+// Timetable of all buses which are in service.
+type Schedule struct {
+	buses []Bus
+}
+
+// Build the timetable of the given buses.
+//
+// Returns an error if a bus in service has no valid - ie positive - roundtrip
+// time, as it would never depart.
+func newSchedule(buses []Bus) (Schedule, error) {
+	var schedule Schedule
+
+	for _, bus := range buses {
+		if bus.inService {
+			if bus.id < 1 {
+				return Schedule{}, fmt.Errorf("Bus %d has no valid roundtrip time", bus.id)
+			}
+
+			schedule.buses = append(schedule.buses, bus)
+		}
+	}
+
+	return schedule, nil
+}
+
+// Generates departures in the order of `DeparturesBetween`, one at a time.
+//
+// It holds the next departure of each bus in a min-heap, so only ever needs
+// memory proportional to the number of buses.
+type departureIterator struct {
+	next departureHeap
+}
+
+// Iterate over all departures at or after `from`.
+func (schedule Schedule) departuresFrom(from int) *departureIterator {
+	iterator := departureIterator{}
+
+	for _, bus := range schedule.buses {
+		// Roundtrip times were validated by `newSchedule`
+		start, _ := bus.NextStart(from, true)
+		iterator.next = append(iterator.next, Departure{Time: start, Bus: bus.id})
+	}
+	heap.Init(&iterator.next)
+
+	return &iterator
+}
+
+// Return the next departure without consuming it. Returns false if there are
+// no buses at all.
+func (iterator *departureIterator) Peek() (Departure, bool) {
+	if len(iterator.next) == 0 {
+		return Departure{}, false
+	}
+
+	return iterator.next[0], true
+}
+
+// Return and consume the next departure. Returns false if there are no buses
+// at all.
+func (iterator *departureIterator) Next() (Departure, bool) {
+	departure, ok := iterator.Peek()
+	if !ok {
+		return Departure{}, false
+	}
+
+	// The bus departs again one roundtrip later
+	iterator.next[0].Time += departure.Bus
+	heap.Fix(&iterator.next, 0)
+
+	return departure, true
+}
+
+// Min-heap of departures, ordered by time and bus ID. See `container/heap`.
+type departureHeap []Departure
+
+func (h departureHeap) Len() int {
+	return len(h)
+}
+
+func (h departureHeap) Less(i, j int) bool {
+	if h[i].Time != h[j].Time {
+		return h[i].Time < h[j].Time
+	}
+	return h[i].Bus < h[j].Bus
+}
+
+func (h departureHeap) Swap(i, j int) {
+	h[i], h[j] = h[j], h[i]
+}
+
+func (h *departureHeap) Push(x any) {
+	*h = append(*h, x.(Departure))
+}
+
+func (h *departureHeap) Pop() any {
+	old := *h
+	x := old[len(old)-1]
+	*h = old[:len(old)-1]
+
+	return x
+}
+
+// Returns all departures in the closed interval [t0, t1], ordered by time.
+// Simultaneous departures are ordered by bus ID.
+func (schedule Schedule) DeparturesBetween(t0 int, t1 int) []Departure {
+	var departures []Departure
+
+	iterator := schedule.departuresFrom(t0)
+	for {
+		departure, ok := iterator.Next()
+		if !ok || departure.Time > t1 {
+			break
+		}
+
+		departures = append(departures, departure)
+	}
+
+	return departures
+}
+
+// Find the earliest departure time t in [from, until] such that at least `k`
+// distinct buses depart within the window [t, t + window]. Windows thus always
+// start with a departure.
+//
+// Returns the time, and the departures within the window. Returns an error if
+// there is no such time within the searched interval.
+func (schedule Schedule) FirstWindowWith(k int, window int, from int, until int) (int, []Departure, error) {
+	// It is sufficient to check windows starting at each departure. As
+	// the windows' starts only ever move forward, so do their ends,
+	// allowing us to maintain the departures within the window, and the
+	// number of buses among them, incrementally. Departures are generated
+	// lazily, so only those up to the end of the first qualifying window
+	// are ever looked at.
+	iterator := schedule.departuresFrom(from)
+
+	// Departures within the current window, the first one being its start
+	var inWindow []Departure
+	departuresPerBus := make(map[int]int)
+
+	for {
+		if len(inWindow) == 0 {
+			departure, ok := iterator.Next()
+			if !ok {
+				break
+			}
+
+			inWindow = append(inWindow, departure)
+			departuresPerBus[departure.Bus] += 1
+		}
+
+		start := inWindow[0]
+		if start.Time > until {
+			break
+		}
+
+		for {
+			departure, ok := iterator.Peek()
+			if !ok || departure.Time > start.Time+window {
+				break
+			}
+
+			iterator.Next()
+			inWindow = append(inWindow, departure)
+			departuresPerBus[departure.Bus] += 1
+		}
+
+		if len(departuresPerBus) >= k {
+			return start.Time, inWindow, nil
+		}
+
+		inWindow = inWindow[1:]
+		departuresPerBus[start.Bus] -= 1
+		if departuresPerBus[start.Bus] == 0 {
+			delete(departuresPerBus, start.Bus)
+		}
+	}
+
+	return 0, nil, fmt.Errorf("No %d buses depart within %d minutes of each other between %d and %d", k, window, from, until)
+}
+
+// Find the earliest time t >= now at which bus `b` departs, such that bus `a`
+// departs exactly `d` minutes later.
+//
+// Returns an error if either bus isn't in service, or if this never happens.
+func (schedule Schedule) NextDepartureAfter(a int, b int, d int, now int) (int, error) {
+	for _, id := range []int{a, b} {
+		if !schedule.hasBus(id) {
+			return 0, fmt.Errorf("Bus %d is not in service", id)
+		}
+	}
+
+	// b departs at t iff t == 0 mod b, and a departs at t + d iff
+	// t == -d mod a.
+	t, period, err := numtheory.CRT([]int{0, -d}, []int{b, a})
+	if err != nil {
+		return 0, fmt.Errorf("Bus %d never departs %d minutes after bus %d: %v", a, d, b, err)
+	}
+
+	// This repeats every `period` minutes, so we move to the first
+	// occurrence at or after `now` - which might be before t, as `now`
+	// can be negative.
+	t = now + numtheory.Mod(t-now, period)
+
+	return t, nil
+}
+
+func (schedule Schedule) hasBus(id int) bool {
+	for _, bus := range schedule.buses {
+		if bus.id == id {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,172 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func mustNewSchedule(t *testing.T, line string) Schedule {
+	schedule, err := newSchedule(mustParseBuses(t, line))
+	assert.NoError(t, err)
+
+	return schedule
+}
+
+func TestNextStart(t *testing.T) {
+	bus := Bus{id: 7, inService: true}
+
+	for _, example := range []struct {
+		now       int
+		inclusive bool
+		expected  int
+	}{
+		{10, true, 14},
+		{10, false, 14},
+		{14, true, 14},
+		{14, false, 21},
+		{-3, true, 0},
+		{0, true, 0},
+		{0, false, 7},
+	} {
+		start, err := bus.NextStart(example.now, example.inclusive)
+		assert.NoError(t, err)
+		assert.Equal(t, example.expected, start, "%+v", example)
+	}
+
+	for _, id := range []int{0, -5} {
+		_, err := Bus{id: id, inService: true}.NextStart(10, true)
+		assert.Error(t, err)
+	}
+}
+
+func TestNewScheduleInvalid(t *testing.T) {
+	for _, id := range []int{0, -5} {
+		_, err := newSchedule([]Bus{{id: 7, inService: true}, {id: id, inService: true}})
+		assert.Error(t, err)
+	}
+
+	// Buses which aren't in service have no ID
+	schedule, err := newSchedule([]Bus{{id: 7, inService: true}, {inService: false}})
+	assert.NoError(t, err)
+	assert.Len(t, schedule.DeparturesBetween(0, 14), 3)
+}
+
+func TestDeparturesBetween(t *testing.T) {
+	schedule := mustNewSchedule(t, "3,x,5")
+
+	assert.Equal(t, []Departure{
+		{Time: 9, Bus: 3},
+		{Time: 10, Bus: 5},
+		{Time: 12, Bus: 3},
+		{Time: 15, Bus: 3},
+		{Time: 15, Bus: 5},
+	}, schedule.DeparturesBetween(9, 15))
+
+	assert.Empty(t, schedule.DeparturesBetween(16, 17))
+	assert.Empty(t, schedule.DeparturesBetween(15, 14))
+}
+
+func TestFirstWindowWith(t *testing.T) {
+	schedule := mustNewSchedule(t, "7,13,x,x,59,x,31,19")
+
+	{
+		// Buses 13 and 7 depart within 1 minute of each other at 13/14
+		start, departures, err := schedule.FirstWindowWith(2, 1, 1, 1000)
+		assert.NoError(t, err)
+		assert.Equal(t, 13, start)
+		assert.Equal(t, []Departure{{Time: 13, Bus: 13}, {Time: 14, Bus: 7}}, departures)
+	}
+
+	{
+		// All buses depart at 0
+		start, departures, err := schedule.FirstWindowWith(5, 0, 0, 1000)
+		assert.NoError(t, err)
+		assert.Equal(t, 0, start)
+		assert.Len(t, departures, 5)
+	}
+
+	{
+		// Task two's solution has all five buses depart within 7
+		// minutes, but there are far earlier such windows.
+		start, departures, err := schedule.FirstWindowWith(5, 7, 1, 1068781)
+		assert.NoError(t, err)
+		assert.Equal(t, 527, start)
+		assert.Equal(t, []Departure{
+			{Time: 527, Bus: 31},
+			{Time: 531, Bus: 59},
+			{Time: 532, Bus: 7},
+			{Time: 532, Bus: 19},
+			{Time: 533, Bus: 13},
+		}, departures)
+	}
+
+	{
+		_, _, err := schedule.FirstWindowWith(3, 0, 1, 100)
+		assert.Error(t, err)
+	}
+
+	{
+		// Departures are generated lazily, so a huge search interval
+		// costs nothing if the window is found early.
+		start, departures, err := schedule.FirstWindowWith(5, 7, 1, 1<<50)
+		assert.NoError(t, err)
+		assert.Equal(t, 527, start)
+		assert.Len(t, departures, 5)
+	}
+
+	{
+		empty := mustNewSchedule(t, "x,x")
+		_, _, err := empty.FirstWindowWith(1, 10, 0, 100)
+		assert.Error(t, err)
+		assert.Empty(t, empty.DeparturesBetween(0, 100))
+	}
+}
+
+func TestNextDepartureAfter(t *testing.T) {
+	schedule := mustNewSchedule(t, "7,13,x,x,59,x,31,19,4,6")
+
+	{
+		// Bus 13 departs one minute after bus 7 at 77 / 78 first
+		start, err := schedule.NextDepartureAfter(13, 7, 1, 0)
+		assert.NoError(t, err)
+		assert.Equal(t, 77, start)
+
+		// And then again every 7 * 13 minutes
+		start, err = schedule.NextDepartureAfter(13, 7, 1, 78)
+		assert.NoError(t, err)
+		assert.Equal(t, 77+91, start)
+
+		start, err = schedule.NextDepartureAfter(13, 7, 1, 77)
+		assert.NoError(t, err)
+		assert.Equal(t, 77, start)
+
+		// Negative times work too, the previous occurrence being at
+		// 77 - 91
+		start, err = schedule.NextDepartureAfter(13, 7, 1, -100)
+		assert.NoError(t, err)
+		assert.Equal(t, -14, start)
+
+		start, err = schedule.NextDepartureAfter(13, 7, 1, -14)
+		assert.NoError(t, err)
+		assert.Equal(t, -14, start)
+	}
+
+	{
+		// Bus 6 departs 2 minutes after bus 4 at 4 / 6, 16 / 18, ...
+		start, err := schedule.NextDepartureAfter(6, 4, 2, 5)
+		assert.NoError(t, err)
+		assert.Equal(t, 16, start)
+	}
+
+	{
+		// Buses 4 and 6 only ever depart at even times
+		_, err := schedule.NextDepartureAfter(6, 4, 1, 0)
+		assert.Error(t, err)
+	}
+
+	{
+		_, err := schedule.NextDepartureAfter(5, 4, 1, 0)
+		assert.Error(t, err)
+	}
+}
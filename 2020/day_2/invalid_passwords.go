@@ -1,7 +1,9 @@
 package main
 
+import "flag"
 import "fmt"
 import "io/ioutil"
+import "os"
 import "regexp"
 import "strconv"
 import "strings"
@@ -13,73 +15,48 @@ type Password struct {
 	SpecFieldEnd   int
 	Char           string
 	Password       string
-}
 
-// Validation rules as per part 1:
-// Password must contain given character between SpecFieldStart and
-// SpecFieldEnd many times.
-func (password Password) validate() bool {
-	count := strings.Count(password.Password, password.Char)
-	return count >= password.SpecFieldStart && count <= password.SpecFieldEnd
+	// One-based line of the input the password was read from
+	Line int
 }
 
-// Validation rules as per part 2:
-// Exactly one of (one-indexed) SpecFieldStart OR SpecFieldEnd characters of
-// password must be given character.
-func (password Password) validateAlt() bool {
-	matchingChars := 0
-	// Abusing the fact that passwords are ASCII only, else we'd have to work with runes.
-	char := password.Char[0]
-
-	// MinCount and MaxCount are 1-based indices
-	if password.Password[password.SpecFieldStart-1] == char {
-		matchingChars += 1
-	}
-
-	if password.Password[password.SpecFieldEnd-1] == char {
-		matchingChars += 1
-	}
-
-	return matchingChars == 1
-}
+var policyName = flag.String("policy", "", "Validate passwords with this policy only, instead of solving both tasks")
 
 func main() {
-	taskOne()
-	taskTwo()
-}
+	flag.Parse()
 
-func taskOne() {
-	fmt.Println("== Task one ==")
+	passwords, err := loadPasswords(inputFile)
+	check(err)
 
-	validCount := 0
-	invalidCount := 0
+	if *policyName != "" {
+		policy, err := policyByName(*policyName)
+		check(err)
 
-	passwords := getPasswords()
-	for _, password := range passwords {
-		if password.validate() {
-			validCount += 1
-		} else {
-			invalidCount += 1
-		}
+		fmt.Printf("== Policy %s ==\n", *policyName)
+		report(passwords, policy)
+		return
 	}
 
-	fmt.Printf(
-		"Checked %d passwords.\n\tValid: %d\n\tInvalid: %d\n\t",
-		validCount+invalidCount,
-		validCount,
-		invalidCount,
-	)
-}
+	fmt.Println("== Task one ==")
+	report(passwords, CountPolicy{})
 
-func taskTwo() {
 	fmt.Println("== Task two ==")
+	report(passwords, PositionPolicy{})
+}
 
+// Validate all passwords, and print how many of them are valid. Passwords
+// which the policy can't be applied to are counted as invalid.
+func report(passwords []Password, policy Policy) {
 	validCount := 0
 	invalidCount := 0
 
-	passwords := getPasswords()
 	for _, password := range passwords {
-		if password.validateAlt() {
+		valid, err := policy.Validate(password)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Line %d: %v\n", password.Line, err)
+		}
+
+		if valid {
 			validCount += 1
 		} else {
 			invalidCount += 1
@@ -100,42 +77,53 @@ func check(e error) {
 	}
 }
 
-func getPasswords() []Password {
-	data, err := ioutil.ReadFile(inputFile)
-	// Remove trailing newline
-	data = data[:len(data)-1]
-	check(err)
+// Load passwords, one per line, preceded by their policy's specification, eg
+// "1-3 a: abcde". Blank lines are skipped.
+//
+// Returns an error, with the offending line, if a line doesn't match this
+// format.
+func loadPasswords(path string) ([]Password, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
 
 	var passwords []Password
 
-	matcher := regexp.MustCompile(`^(\d+)-(\d+) ([a-z]): ([a-z]+)$`)
+	matcher := regexp.MustCompile(`^(\d+)-(\d+) (\S+): (\S*)$`)
 	// ioutil.ReadFile returns a byte slice, strings.Split expects a string
-	for _, s := range strings.Split(string(data), "\n") {
+	for idx, s := range strings.Split(string(data), "\n") {
+		s = strings.TrimSuffix(s, "\r")
+		if strings.TrimSpace(s) == "" {
+			continue
+		}
+
 		match := matcher.FindStringSubmatch(s)
 		if match == nil {
-			panic(fmt.Sprintf("Line did not match pattern: %v\n", s))
+			return nil, fmt.Errorf("Line %d did not match pattern: %q", idx+1, s)
 		}
 
 		minCount, err := strconv.Atoi(match[1])
-		check(err)
+		if err != nil {
+			return nil, fmt.Errorf("Line %d: %v", idx+1, err)
+		}
 
 		maxCount, err := strconv.Atoi(match[2])
-		check(err)
-
-		char := match[3]
-
-		password := match[4]
+		if err != nil {
+			return nil, fmt.Errorf("Line %d: %v", idx+1, err)
+		}
 
 		passwords = append(
 			passwords,
 			Password{
 				SpecFieldStart: minCount,
 				SpecFieldEnd:   maxCount,
-				Char:           char,
-				Password:       password,
+				Char:           match[3],
+				Password:       match[4],
+				Line:           idx + 1,
 			},
 		)
 	}
 
-	return passwords
+	return passwords, nil
 }
@@ -0,0 +1,189 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Interpretation of the policy specification of a password line, eg
+// "1-3 a".
+type Policy interface {
+	// Whether the password adheres to its specification.
+	//
+	// Returns an error if the specification can't be applied to the
+	// password, eg as it refers to a position beyond its end.
+	Validate(password Password) (bool, error)
+}
+
+// Constructors of the available policies, by name. Each call returns a new
+// instance, so policies with internal state aren't shared.
+var policies = map[string]func() Policy{
+	"count":            func() Policy { return CountPolicy{} },
+	"position":         func() Policy { return PositionPolicy{} },
+	"unicode-position": func() Policy { return UnicodePositionPolicy{} },
+	"regex":            func() Policy { return newRegexPolicy() },
+	"class":            func() Policy { return ClassPolicy{} },
+}
+
+// Return a new instance of the policy with the given name, see `policies`.
+func policyByName(name string) (Policy, error) {
+	newPolicy, ok := policies[name]
+	if !ok {
+		names := make([]string, 0, len(policies))
+		for name := range policies {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		return nil, fmt.Errorf("Unknown policy %q, expected one of %s", name, strings.Join(names, ", "))
+	}
+
+	return newPolicy(), nil
+}
+
+// Validation rules as per part 1:
+// Password must contain given character between SpecFieldStart and
+// SpecFieldEnd many times.
+type CountPolicy struct{}
+
+func (policy CountPolicy) Validate(password Password) (bool, error) {
+	count := strings.Count(password.Password, password.Char)
+	return count >= password.SpecFieldStart && count <= password.SpecFieldEnd, nil
+}
+
+// Validation rules as per part 2:
+// Exactly one of (one-indexed) SpecFieldStart OR SpecFieldEnd characters of
+// password must be given character.
+//
+// Positions refer to bytes, so this is only meaningful for ASCII passwords.
+// See `UnicodePositionPolicy` otherwise.
+type PositionPolicy struct{}
+
+func (policy PositionPolicy) Validate(password Password) (bool, error) {
+	if len(password.Char) != 1 {
+		return false, fmt.Errorf("Position policy requires a single-byte character, got %q", password.Char)
+	}
+
+	return exactlyOneAt([]byte(password.Password), password.Char[0], password.SpecFieldStart, password.SpecFieldEnd)
+}
+
+// Like `PositionPolicy`, but with positions referring to characters rather
+// than bytes.
+type UnicodePositionPolicy struct{}
+
+func (policy UnicodePositionPolicy) Validate(password Password) (bool, error) {
+	if utf8.RuneCountInString(password.Char) != 1 {
+		return false, fmt.Errorf("Position policy requires a single character, got %q", password.Char)
+	}
+	char, _ := utf8.DecodeRuneInString(password.Char)
+
+	return exactlyOneAt([]rune(password.Password), char, password.SpecFieldStart, password.SpecFieldEnd)
+}
+
+// Whether exactly one of the one-based positions `a` and `b` of `s` holds
+// `char`.
+//
+// Returns an error if either position is out of range.
+func exactlyOneAt[T comparable](s []T, char T, a int, b int) (bool, error) {
+	matchingChars := 0
+
+	for _, pos := range []int{a, b} {
+		if pos < 1 || pos > len(s) {
+			return false, fmt.Errorf("Position %d out of range for password of length %d", pos, len(s))
+		}
+
+		if s[pos-1] == char {
+			matchingChars += 1
+		}
+	}
+
+	return matchingChars == 1, nil
+}
+
+// Maximum number of compiled expressions a `RegexPolicy` keeps around.
+const maxCachedPatterns int = 256
+
+// Like `CountPolicy`, but with the given character being a regular
+// expression, eg "1-3 a+b". The number of non-overlapping matches must be
+// between SpecFieldStart and SpecFieldEnd.
+//
+// Safe for concurrent use.
+type RegexPolicy struct {
+	// Compiled expressions, as the same few tend to be used for many
+	// passwords. Once full, further expressions are compiled on every
+	// use instead.
+	cache map[string]*regexp.Regexp
+	mutex sync.Mutex
+}
+
+func newRegexPolicy() *RegexPolicy {
+	return &RegexPolicy{cache: make(map[string]*regexp.Regexp)}
+}
+
+// Return the compiled expression, from the cache if possible.
+func (policy *RegexPolicy) compile(pattern string) (*regexp.Regexp, error) {
+	policy.mutex.Lock()
+	defer policy.mutex.Unlock()
+
+	if matcher, ok := policy.cache[pattern]; ok {
+		return matcher, nil
+	}
+
+	matcher, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(policy.cache) < maxCachedPatterns {
+		policy.cache[pattern] = matcher
+	}
+
+	return matcher, nil
+}
+
+func (policy *RegexPolicy) Validate(password Password) (bool, error) {
+	matcher, err := policy.compile(password.Char)
+	if err != nil {
+		return false, err
+	}
+
+	count := len(matcher.FindAllStringIndex(password.Password, -1))
+	return count >= password.SpecFieldStart && count <= password.SpecFieldEnd, nil
+}
+
+// Character classes of `ClassPolicy`, by name.
+var characterClasses = map[string]func(rune) bool{
+	"letter": unicode.IsLetter,
+	"upper":  unicode.IsUpper,
+	"lower":  unicode.IsLower,
+	"digit":  unicode.IsDigit,
+	"space":  unicode.IsSpace,
+	"punct":  unicode.IsPunct,
+	"symbol": unicode.IsSymbol,
+}
+
+// Like `CountPolicy`, but with the given character being the name of a
+// character class, eg "2-4 digit". The number of characters within that class
+// must be between SpecFieldStart and SpecFieldEnd.
+type ClassPolicy struct{}
+
+func (policy ClassPolicy) Validate(password Password) (bool, error) {
+	inClass, ok := characterClasses[password.Char]
+	if !ok {
+		return false, fmt.Errorf("Unknown character class %q", password.Char)
+	}
+
+	count := 0
+	for _, char := range password.Password {
+		if inClass(char) {
+			count += 1
+		}
+	}
+
+	return count >= password.SpecFieldStart && count <= password.SpecFieldEnd, nil
+}
@@ -0,0 +1,135 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func validateAll(t *testing.T, policy Policy, passwords []Password) []bool {
+	var results []bool
+	for _, password := range passwords {
+		valid, err := policy.Validate(password)
+		assert.NoError(t, err)
+		results = append(results, valid)
+	}
+
+	return results
+}
+
+func TestExamplePolicies(t *testing.T) {
+	passwords, err := loadPasswords("invalid_passwords.input.test1")
+	assert.NoError(t, err)
+	assert.Len(t, passwords, 3)
+	assert.Equal(t, Password{SpecFieldStart: 2, SpecFieldEnd: 9, Char: "c", Password: "ccccccccc", Line: 3}, passwords[2])
+
+	assert.Equal(t, []bool{true, false, true}, validateAll(t, CountPolicy{}, passwords))
+	assert.Equal(t, []bool{true, false, false}, validateAll(t, PositionPolicy{}, passwords))
+	assert.Equal(t, []bool{true, false, false}, validateAll(t, UnicodePositionPolicy{}, passwords))
+	assert.Equal(t, []bool{true, false, true}, validateAll(t, newRegexPolicy(), passwords))
+}
+
+func TestPositionPolicyErrors(t *testing.T) {
+	for _, policy := range []Policy{PositionPolicy{}, UnicodePositionPolicy{}} {
+		_, err := policy.Validate(Password{SpecFieldStart: 1, SpecFieldEnd: 6, Char: "a", Password: "abcde"})
+		assert.EqualError(t, err, "Position 6 out of range for password of length 5")
+
+		_, err = policy.Validate(Password{SpecFieldStart: 0, SpecFieldEnd: 2, Char: "a", Password: "abcde"})
+		assert.Error(t, err)
+
+		_, err = policy.Validate(Password{SpecFieldStart: 1, SpecFieldEnd: 2, Char: "ab", Password: "abcde"})
+		assert.Error(t, err)
+	}
+}
+
+func TestUnicodePositionPolicy(t *testing.T) {
+	password := Password{SpecFieldStart: 2, SpecFieldEnd: 4, Char: "ü", Password: "gürtel"}
+
+	valid, err := UnicodePositionPolicy{}.Validate(password)
+	assert.NoError(t, err)
+	assert.True(t, valid)
+
+	// Byte-wise, 'ü' is two characters long
+	_, err = PositionPolicy{}.Validate(password)
+	assert.Error(t, err)
+}
+
+func TestRegexPolicy(t *testing.T) {
+	policy := newRegexPolicy()
+
+	valid, err := policy.Validate(Password{SpecFieldStart: 2, SpecFieldEnd: 2, Char: "ab+", Password: "abbxabyaa"})
+	assert.NoError(t, err)
+	assert.True(t, valid)
+
+	valid, err = policy.Validate(Password{SpecFieldStart: 3, SpecFieldEnd: 5, Char: "ab+", Password: "abbxabyaa"})
+	assert.NoError(t, err)
+	assert.False(t, valid)
+
+	_, err = policy.Validate(Password{SpecFieldStart: 1, SpecFieldEnd: 2, Char: "a(", Password: "a"})
+	assert.Error(t, err)
+}
+
+func TestClassPolicy(t *testing.T) {
+	valid, err := ClassPolicy{}.Validate(Password{SpecFieldStart: 2, SpecFieldEnd: 3, Char: "digit", Password: "a1b2c"})
+	assert.NoError(t, err)
+	assert.True(t, valid)
+
+	valid, err = ClassPolicy{}.Validate(Password{SpecFieldStart: 2, SpecFieldEnd: 2, Char: "upper", Password: "ÄbÖ"})
+	assert.NoError(t, err)
+	assert.True(t, valid)
+
+	_, err = ClassPolicy{}.Validate(Password{SpecFieldStart: 1, SpecFieldEnd: 2, Char: "vowel", Password: "a"})
+	assert.Error(t, err)
+}
+
+func TestPolicyByName(t *testing.T) {
+	policy, err := policyByName("position")
+	assert.NoError(t, err)
+	assert.Equal(t, PositionPolicy{}, policy)
+
+	_, err = policyByName("length")
+	assert.EqualError(t, err, `Unknown policy "length", expected one of class, count, position, regex, unicode-position`)
+}
+
+func TestLoadPasswordsErrors(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "passwords.input")
+	assert.NoError(t, os.WriteFile(path, []byte("1-3 a: abcde\n1-3: cdefg\n"), 0644))
+
+	_, err := loadPasswords(path)
+	assert.EqualError(t, err, `Line 2 did not match pattern: "1-3: cdefg"`)
+
+	_, err = loadPasswords("does-not-exist.input")
+	assert.Error(t, err)
+}
+
+func TestRegexPolicyConcurrent(t *testing.T) {
+	policy, err := policyByName("regex")
+	assert.NoError(t, err)
+
+	// Each lookup yields a new instance
+	other, err := policyByName("regex")
+	assert.NoError(t, err)
+	assert.NotSame(t, policy, other)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i += 1 {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+
+			for j := 0; j < 100; j += 1 {
+				pattern := strconv.Itoa((i*100 + j) % 300)
+				valid, err := policy.Validate(Password{SpecFieldStart: 1, SpecFieldEnd: 1, Char: pattern, Password: pattern})
+				assert.NoError(t, err)
+				assert.True(t, valid)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	assert.LessOrEqual(t, len(policy.(*RegexPolicy).cache), maxCachedPatterns)
+}
@@ -1,72 +1,89 @@
 package main
 
-import "fmt"
-import "io/ioutil"
-import "strings"
+import (
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strconv"
+	"strings"
+)
 
 const inputFile string = "avoiding_trees.input"
 const tree rune = '#'
 const free rune = '.'
 
-func main() {
-	taskOne()
-	taskTwo()
-}
+var maxDX = flag.Int("max-dx", 0, "Search all slopes up to this horizontal step for the one hitting the fewest trees")
+var maxDY = flag.Int("max-dy", 0, "Search all slopes up to this vertical step for the one hitting the fewest trees")
+var renderSlope = flag.String("render", "", "Render the path of the given slope, eg 3/1, over the map")
 
-func taskOne() {
-	fmt.Println("== Task one ==")
-	treesHit := checkTreesHit(3, 1)
+func main() {
+	flag.Parse()
 
-	fmt.Printf("Hit %d trees\n", treesHit)
-}
+	landscape, err := loadLandscape(inputFile)
+	check(err)
 
-func taskTwo() {
-	fmt.Println("== Task two ==")
+	taskOne(landscape)
+	taskTwo(landscape)
 
-	treesHit1 := checkTreesHit(1, 1)
-	fmt.Printf("Slope 1/1: Hit %d trees\n", treesHit1)
+	if *maxDX > 0 || *maxDY > 0 {
+		fmt.Println("== Best slope ==")
 
-	treesHit2 := checkTreesHit(3, 1)
-	fmt.Printf("Slope 3/1: Hit %d trees\n", treesHit2)
+		slope, treesHit, err := landscape.BestSlope(*maxDX, *maxDY)
+		check(err)
+		fmt.Printf("Slope %s: Hit %d trees\n", slope, treesHit)
+	}
 
-	treesHit3 := checkTreesHit(5, 1)
-	fmt.Printf("Slope 5/1: Hit %d trees\n", treesHit3)
+	if *renderSlope != "" {
+		slope, err := parseSlope(*renderSlope)
+		check(err)
+		check(landscape.RenderPath(os.Stdout, slope))
+	}
+}
 
-	treesHit4 := checkTreesHit(7, 1)
-	fmt.Printf("Slope 7/1: Hit %d trees\n", treesHit4)
+func taskOne(landscape Landscape) {
+	fmt.Println("== Task one ==")
 
-	treesHit5 := checkTreesHit(1, 2)
-	fmt.Printf("Slope 1/2: Hit %d trees\n", treesHit5)
+	treesHit, err := landscape.TreesHit(Slope{DX: 3, DY: 1})
+	check(err)
 
-	fmt.Printf("Product of trees hit: %d\n", treesHit1*treesHit2*treesHit3*treesHit4*treesHit5)
+	fmt.Printf("Hit %d trees\n", treesHit)
 }
 
-func checkTreesHit(dX int, dY int) int {
-	// x is horizontal, y vertical coordinate.
-	// Mind that first index of `landscape` is the *vertical* coordinate,
-	// ie y.
-	landscape := loadLandscape()
-	var x, y int = 0, 0
+func taskTwo(landscape Landscape) {
+	fmt.Println("== Task two ==")
+
+	slopes := []Slope{{1, 1}, {3, 1}, {5, 1}, {7, 1}, {1, 2}}
+	treesHit, err := landscape.EvaluateSlopes(slopes)
+	check(err)
 
-	var width int = len(landscape[0])
-	var height int = len(landscape)
+	product := 1
+	for idx, slope := range slopes {
+		fmt.Printf("Slope %s: Hit %d trees\n", slope, treesHit[idx])
+		product *= treesHit[idx]
+	}
 
-	var treesHit int = 0
+	fmt.Printf("Product of trees hit: %d\n", product)
+}
 
-	for y < height {
-		// fmt.Printf("x = %d, y = %d, Field = %c\n", x, y, landscape[y][x])
+// Parse a slope in the format used by `Slope.String`, eg "3/1".
+func parseSlope(s string) (Slope, error) {
+	dX, dY, ok := strings.Cut(s, "/")
+	if !ok {
+		return Slope{}, fmt.Errorf("Invalid slope %q, expected dX/dY", s)
+	}
 
-		if landscape[y][x] == tree {
-			treesHit += 1
-		}
+	var slope Slope
+	var err error
 
-		// As each row has `width` entries, indices are from 0 to width - 1.
-		// Sum modulo width ensures we wrap around.
-		x = (x + dX) % width
-		y += dY
+	if slope.DX, err = strconv.Atoi(dX); err != nil {
+		return Slope{}, fmt.Errorf("Invalid slope %q: %v", s, err)
+	}
+	if slope.DY, err = strconv.Atoi(dY); err != nil {
+		return Slope{}, fmt.Errorf("Invalid slope %q: %v", s, err)
 	}
 
-	return treesHit
+	return slope, nil
 }
 
 func check(e error) {
@@ -75,21 +92,11 @@ func check(e error) {
 	}
 }
 
-func loadLandscape() [][]rune {
-	var landscape [][]rune
-
-	data, err := ioutil.ReadFile(inputFile)
-	// Remove trailing newline
-	data = data[:len(data)-1]
-	check(err)
-
-	for _, line := range strings.Split(string(data), "\n") {
-		var row []rune
-		for _, thing := range line {
-			row = append(row, thing)
-		}
-		landscape = append(landscape, row)
+func loadLandscape(path string) (Landscape, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return Landscape{}, err
 	}
 
-	return landscape
+	return parseLandscape(string(data))
 }
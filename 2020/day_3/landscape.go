@@ -0,0 +1,232 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"runtime"
+	"strings"
+	"sync"
+
+	"github.com/lavode/adventofcode/2020/pkg/numtheory"
+)
+
+// Map of the area below the airport, which repeats to the right infinitely.
+type Landscape struct {
+	// Mind that the first index is the *vertical* coordinate, ie y.
+	rows [][]rune
+}
+
+// Number of fields per row before the pattern repeats.
+func (landscape Landscape) Width() int {
+	return len(landscape.rows[0])
+}
+
+func (landscape Landscape) Height() int {
+	return len(landscape.rows)
+}
+
+// Parse a landscape of trees ('#') and free fields ('.'). Trailing blank lines
+// are ignored.
+//
+// Returns an error if the landscape is empty, contains other characters, or
+// its rows differ in width.
+func parseLandscape(input string) (Landscape, error) {
+	var landscape Landscape
+
+	input = strings.TrimRight(strings.ReplaceAll(input, "\r\n", "\n"), "\n")
+	if input == "" {
+		return Landscape{}, fmt.Errorf("Empty landscape")
+	}
+
+	for idx, line := range strings.Split(input, "\n") {
+		row := []rune(line)
+
+		for _, thing := range row {
+			if thing != tree && thing != free {
+				return Landscape{}, fmt.Errorf("Line %d: Invalid field '%c'", idx+1, thing)
+			}
+		}
+
+		if idx > 0 && len(row) != len(landscape.rows[0]) {
+			return Landscape{}, fmt.Errorf("Line %d: Expected %d fields, got %d", idx+1, len(landscape.rows[0]), len(row))
+		}
+
+		landscape.rows = append(landscape.rows, row)
+	}
+
+	if landscape.Width() == 0 {
+		return Landscape{}, fmt.Errorf("Empty landscape")
+	}
+
+	return landscape, nil
+}
+
+// Direction of travel, moving `DX` to the right and `DY` down with each step.
+type Slope struct {
+	DX int
+	DY int
+}
+
+func (slope Slope) String() string {
+	return fmt.Sprintf("%d/%d", slope.DX, slope.DY)
+}
+
+// Visit each position along the slope, from the top left until we've passed
+// the bottom.
+func (landscape Landscape) walk(slope Slope, visit func(x int, y int)) error {
+	if slope.DY < 1 {
+		return fmt.Errorf("Slope %s does not move down", slope)
+	}
+
+	// x is horizontal, y vertical coordinate.
+	for x, y := 0, 0; y < landscape.Height(); y += slope.DY {
+		visit(x, y)
+
+		// As each row has `width` entries, indices are from 0 to
+		// width - 1. Sum modulo width ensures we wrap around, in either
+		// direction.
+		x = numtheory.Mod(x+slope.DX, landscape.Width())
+	}
+
+	return nil
+}
+
+// Number of trees hit when following the slope from the top left.
+//
+// Returns an error if the slope does not move down, as we'd never arrive.
+func (landscape Landscape) TreesHit(slope Slope) (int, error) {
+	treesHit := 0
+
+	err := landscape.walk(slope, func(x int, y int) {
+		if landscape.rows[y][x] == tree {
+			treesHit += 1
+		}
+	})
+
+	return treesHit, err
+}
+
+// Number of trees hit for each of the slopes, which are evaluated
+// concurrently.
+//
+// Returns an error if any of the slopes does not move down.
+func (landscape Landscape) EvaluateSlopes(slopes []Slope) ([]int, error) {
+	treesHit := make([]int, len(slopes))
+	errs := make([]error, len(slopes))
+
+	// The landscape is only ever read, so can safely be shared between
+	// workers. Each worker writes to distinct indices of the results.
+	indices := make(chan int)
+	var wg sync.WaitGroup
+
+	for i := 0; i < min(runtime.NumCPU(), len(slopes)); i += 1 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			for idx := range indices {
+				treesHit[idx], errs[idx] = landscape.TreesHit(slopes[idx])
+			}
+		}()
+	}
+
+	for idx := range slopes {
+		indices <- idx
+	}
+	close(indices)
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return treesHit, nil
+}
+
+// Find the slope hitting the fewest trees, out of all slopes with
+// 0 <= dX <= maxDX and 1 <= dY <= maxDY. Ties are broken in favour of the
+// smallest dY, then the smallest dX.
+//
+// Returns the slope and the number of trees it hits, or an error if the
+// bounds contain no slope.
+func (landscape Landscape) BestSlope(maxDX int, maxDY int) (Slope, int, error) {
+	var slopes []Slope
+	for dY := 1; dY <= maxDY; dY += 1 {
+		for dX := 0; dX <= maxDX; dX += 1 {
+			slopes = append(slopes, Slope{DX: dX, DY: dY})
+		}
+	}
+
+	if len(slopes) == 0 {
+		return Slope{}, 0, fmt.Errorf("No slopes with dX <= %d and 1 <= dY <= %d", maxDX, maxDY)
+	}
+
+	treesHit, err := landscape.EvaluateSlopes(slopes)
+	if err != nil {
+		return Slope{}, 0, err
+	}
+
+	best := 0
+	for idx := range slopes {
+		if treesHit[idx] < treesHit[best] {
+			best = idx
+		}
+	}
+
+	return slopes[best], treesHit[best], nil
+}
+
+// Render the landscape, with fields visited along the slope marked 'O' if
+// free and 'X' if a tree was hit.
+//
+// The landscape is repeated to the right as often as needed to show the path
+// without wrapping around.
+func (landscape Landscape) RenderPath(w io.Writer, slope Slope) error {
+	visited := make(map[[2]int]bool)
+	maxX := 0
+
+	// Track the path without wrapping around, so we know how often to
+	// repeat the landscape.
+	steps := 0
+	err := landscape.walk(slope, func(_ int, y int) {
+		x := steps * slope.DX
+		visited[[2]int{x, y}] = true
+		maxX = max(maxX, x)
+		steps += 1
+	})
+	if err != nil {
+		return err
+	}
+
+	// Leftward slopes start further right instead, at a multiple of the
+	// width to keep the path aligned with the repeated landscape.
+	offset := 0
+	if slope.DX < 0 {
+		offset = (-(steps-1)*slope.DX + landscape.Width() - 1) / landscape.Width() * landscape.Width()
+		maxX = offset
+	}
+
+	repeats := maxX/landscape.Width() + 1
+
+	var out strings.Builder
+	for y, row := range landscape.rows {
+		for x := 0; x < repeats*landscape.Width(); x += 1 {
+			thing := row[x%landscape.Width()]
+
+			switch {
+			case !visited[[2]int{x - offset, y}]:
+				out.WriteRune(thing)
+			case thing == tree:
+				out.WriteRune('X')
+			default:
+				out.WriteRune('O')
+			}
+		}
+		out.WriteRune('\n')
+	}
+
+	_, err = io.WriteString(w, out.String())
+	return err
+}
@@ -0,0 +1,109 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func exampleLandscape(t *testing.T) Landscape {
+	landscape, err := loadLandscape("avoiding_trees.input.test1")
+	assert.NoError(t, err)
+
+	return landscape
+}
+
+func TestTreesHit(t *testing.T) {
+	landscape := exampleLandscape(t)
+	assert.Equal(t, 11, landscape.Width())
+	assert.Equal(t, 11, landscape.Height())
+
+	treesHit, err := landscape.TreesHit(Slope{DX: 3, DY: 1})
+	assert.NoError(t, err)
+	assert.Equal(t, 7, treesHit)
+
+	// Moving left wraps around too
+	treesHit, err = landscape.TreesHit(Slope{DX: -8, DY: 1})
+	assert.NoError(t, err)
+	assert.Equal(t, 7, treesHit)
+
+	_, err = landscape.TreesHit(Slope{DX: 1, DY: 0})
+	assert.Error(t, err)
+}
+
+func TestEvaluateSlopes(t *testing.T) {
+	landscape := exampleLandscape(t)
+
+	treesHit, err := landscape.EvaluateSlopes([]Slope{{1, 1}, {3, 1}, {5, 1}, {7, 1}, {1, 2}})
+	assert.NoError(t, err)
+	assert.Equal(t, []int{2, 7, 3, 4, 2}, treesHit)
+
+	treesHit, err = landscape.EvaluateSlopes(nil)
+	assert.NoError(t, err)
+	assert.Empty(t, treesHit)
+
+	_, err = landscape.EvaluateSlopes([]Slope{{1, 1}, {1, -1}})
+	assert.Error(t, err)
+}
+
+func TestBestSlope(t *testing.T) {
+	landscape := exampleLandscape(t)
+
+	slope, treesHit, err := landscape.BestSlope(7, 2)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, treesHit)
+	assert.Equal(t, Slope{DX: 5, DY: 2}, slope)
+
+	// The first slope wins ties
+	slope, treesHit, err = landscape.BestSlope(0, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, Slope{DX: 0, DY: 1}, slope)
+	assert.Equal(t, 3, treesHit)
+
+	_, _, err = landscape.BestSlope(3, 0)
+	assert.Error(t, err)
+}
+
+func TestRenderPath(t *testing.T) {
+	landscape := exampleLandscape(t)
+
+	var out bytes.Buffer
+	assert.NoError(t, landscape.RenderPath(&out, Slope{DX: 3, DY: 1}))
+
+	lines := strings.Split(strings.TrimSuffix(out.String(), "\n"), "\n")
+	assert.Len(t, lines, 11)
+	assert.Equal(t, "O.##.........##.........##.......", lines[0])
+	assert.Equal(t, "#..O#...#..#...#...#..#...#...#..", lines[1])
+	assert.Equal(t, ".#....X..#..#....#..#..#....#..#.", lines[2])
+	assert.Equal(t, 7, strings.Count(out.String(), "X"))
+
+	// Leftward paths stay aligned with the landscape
+	out.Reset()
+	assert.NoError(t, landscape.RenderPath(&out, Slope{DX: -8, DY: 1}))
+	assert.Equal(t, 7, strings.Count(out.String(), "X"))
+}
+
+func TestParseLandscapeErrors(t *testing.T) {
+	_, err := parseLandscape("..#\n.#\n")
+	assert.EqualError(t, err, "Line 2: Expected 3 fields, got 2")
+
+	_, err = parseLandscape("..#\n.o.\n")
+	assert.EqualError(t, err, "Line 2: Invalid field 'o'")
+
+	_, err = parseLandscape("\n")
+	assert.Error(t, err)
+}
+
+func TestParseSlope(t *testing.T) {
+	slope, err := parseSlope("3/1")
+	assert.NoError(t, err)
+	assert.Equal(t, Slope{DX: 3, DY: 1}, slope)
+	assert.Equal(t, "3/1", slope.String())
+
+	_, err = parseSlope("3")
+	assert.Error(t, err)
+	_, err = parseSlope("3/x")
+	assert.Error(t, err)
+}
@@ -0,0 +1,104 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Error in the passport records, with the one-based line where it was
+// encountered.
+type ParseError struct {
+	Line int
+	Msg  string
+}
+
+func (err ParseError) Error() string {
+	return fmt.Sprintf("Line %d: %s", err.Line, err.Msg)
+}
+
+// Parse passport records.
+//
+// Each record consists of key:value pairs separated by any whitespace, and
+// may span multiple lines. Records are separated by blank lines. Both LF and
+// CRLF line endings are accepted, and the last record need not be followed by
+// a blank line.
+//
+// Returns a `ParseError` on pairs without key or ':', and on keys occurring
+// more than once within a record.
+func parsePassports(input string) ([]Passport, error) {
+	var passports []Passport
+
+	var passport Passport
+	// Line on which each key of the current passport was seen
+	seen := make(map[string]int)
+
+	for idx, line := range strings.Split(input, "\n") {
+		lineNumber := idx + 1
+		line = strings.TrimSuffix(line, "\r")
+
+		pairs := strings.Fields(line)
+		if len(pairs) == 0 {
+			// Individual passports are separated by blank lines,
+			// though there might be multiple of them.
+			if passport.firstLine != 0 {
+				passports = append(passports, passport)
+				passport = Passport{}
+				seen = make(map[string]int)
+			}
+
+			continue
+		}
+
+		if passport.firstLine == 0 {
+			passport.firstLine = lineNumber
+		}
+		passport.lastLine = lineNumber
+
+		for _, pair := range pairs {
+			key, value, ok := strings.Cut(pair, ":")
+			if !ok || key == "" {
+				return nil, ParseError{Line: lineNumber, Msg: fmt.Sprintf("Malformed pair %q, expected key:value", pair)}
+			}
+
+			if previous, ok := seen[key]; ok {
+				return nil, ParseError{Line: lineNumber, Msg: fmt.Sprintf("Duplicate key %s, first seen on line %d", key, previous)}
+			}
+			seen[key] = lineNumber
+
+			passport.set(key, value)
+		}
+	}
+
+	if passport.firstLine != 0 {
+		passports = append(passports, passport)
+	}
+
+	return passports, nil
+}
+
+// Set the field with the given name, keeping unknown fields as extras.
+func (passport *Passport) set(key string, value string) {
+	switch key {
+	case "byr":
+		passport.byr = value
+	case "iyr":
+		passport.iyr = value
+	case "eyr":
+		passport.eyr = value
+	case "hgt":
+		passport.hgt = value
+	case "hcl":
+		passport.hcl = value
+	case "ecl":
+		passport.ecl = value
+	case "pid":
+		passport.pid = value
+	case "cid":
+		passport.cid = value
+	default:
+		if passport.extras == nil {
+			passport.extras = make(map[string]string)
+		}
+		passport.extras[key] = value
+	}
+}
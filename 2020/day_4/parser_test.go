@@ -0,0 +1,75 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoadPassports(t *testing.T) {
+	// The example lacks a trailing newline, let alone a blank line
+	passports, err := loadPassports("passports.input.test1")
+	assert.NoError(t, err)
+	assert.Len(t, passports, 4)
+
+	present := 0
+	for _, passport := range passports {
+		if passport.requiredFieldsPresent() {
+			present += 1
+		}
+	}
+	assert.Equal(t, 2, present)
+
+	last := passports[3]
+	assert.Equal(t, "59in", last.hgt)
+	assert.Equal(t, 12, last.firstLine)
+	assert.Equal(t, 13, last.lastLine)
+}
+
+func TestParsePassportsWhitespace(t *testing.T) {
+	input := "byr:1937\tiyr:2017\r\n  hgt:183cm  \r\n \t\r\n\r\n\r\necl:gry\r\n\r\n"
+
+	passports, err := parsePassports(input)
+	assert.NoError(t, err)
+	assert.Equal(
+		t,
+		[]Passport{
+			{byr: "1937", iyr: "2017", hgt: "183cm", firstLine: 1, lastLine: 2},
+			{ecl: "gry", firstLine: 6, lastLine: 6},
+		},
+		passports,
+	)
+
+	passports, err = parsePassports("")
+	assert.NoError(t, err)
+	assert.Empty(t, passports)
+}
+
+func TestParsePassportsExtras(t *testing.T) {
+	passports, err := parsePassports("byr:1937 foo:bar\nempty:\n")
+	assert.NoError(t, err)
+	assert.Len(t, passports, 1)
+	assert.Equal(t, map[string]string{"foo": "bar", "empty": ""}, passports[0].extras)
+
+	value, ok := passports[0].Field("foo")
+	assert.True(t, ok)
+	assert.Equal(t, "bar", value)
+
+	_, ok = passports[0].Field("empty")
+	assert.False(t, ok)
+}
+
+func TestParsePassportsErrors(t *testing.T) {
+	_, err := parsePassports("byr:1937\niyr:2017 hgt183cm\n")
+	assert.Equal(t, ParseError{Line: 2, Msg: `Malformed pair "hgt183cm", expected key:value`}, err)
+
+	_, err = parsePassports("byr:1937 :2017\n")
+	assert.Equal(t, ParseError{Line: 1, Msg: `Malformed pair ":2017", expected key:value`}, err)
+
+	_, err = parsePassports("byr:1937\nfoo:1\n\nfoo:2\niyr:2017 foo:3")
+	assert.Equal(t, ParseError{Line: 5, Msg: "Duplicate key foo, first seen on line 4"}, err)
+	assert.EqualError(t, err, "Line 5: Duplicate key foo, first seen on line 4")
+
+	_, err = loadPassports("does-not-exist.input")
+	assert.Error(t, err)
+}
@@ -1,10 +1,9 @@
 package main
 
+import "flag"
 import "fmt"
 import "io/ioutil"
-import "regexp"
-import "strconv"
-import "strings"
+import "os"
 
 const inputFile string = "passports.input"
 
@@ -17,6 +16,14 @@ type Passport struct {
 	ecl string
 	pid string
 	cid string
+
+	// Fields other than the ones above, which are kept such that policies
+	// can refer to them.
+	extras map[string]string
+
+	// One-based range of lines in the input which the passport spans.
+	firstLine int
+	lastLine  int
 }
 
 func (passport Passport) requiredFieldsPresent() bool {
@@ -29,89 +36,40 @@ func (passport Passport) requiredFieldsPresent() bool {
 		passport.pid != ""
 }
 
-func (passport Passport) isValid() bool {
-	// Birth year: Four digits, numeric, 1920 <= x <= 2002
-	byr, err := strconv.Atoi(passport.byr)
-	if err != nil || len(passport.byr) != 4 || byr < 1920 || byr > 2002 {
-		return false
-	}
-
-	// Issue year: Four digits, numeric, 2010 <= x <= 2020
-	iyr, err := strconv.Atoi(passport.iyr)
-	if err != nil || len(passport.iyr) != 4 || iyr < 2010 || iyr > 2020 {
-		return false
-	}
-
-	// Expiration year: Four digits, numeric, 2020 <= x <= 2030
-	eyr, err := strconv.Atoi(passport.eyr)
-	if err != nil || len(passport.eyr) != 4 || eyr < 2020 || eyr > 2030 {
-		return false
-	}
-
-	// Height: Number, followed by 'cm' or 'in'.
-	//   For cm: 150 <= x <= 193
-	//   For in: 59 <= x <= 76
-	heightMatcher := regexp.MustCompile(`^(\d+)(cm|in)$`)
-	match := heightMatcher.FindStringSubmatch(passport.hgt)
-	if match == nil {
-		return false
-	}
-
-	height, err := strconv.Atoi(match[1])
-	if err != nil {
-		return false
-	}
-
-	unit := match[2]
-	switch unit {
-	case "cm":
-		if height < 150 || height > 193 {
-			return false
-		}
-	case "in":
-		if height < 59 || height > 76 {
-			return false
-		}
+// Value of the field with the given name, and whether it is present.
+func (passport Passport) Field(name string) (string, bool) {
+	var value string
+
+	switch name {
+	case "byr":
+		value = passport.byr
+	case "iyr":
+		value = passport.iyr
+	case "eyr":
+		value = passport.eyr
+	case "hgt":
+		value = passport.hgt
+	case "hcl":
+		value = passport.hcl
+	case "ecl":
+		value = passport.ecl
+	case "pid":
+		value = passport.pid
+	case "cid":
+		value = passport.cid
 	default:
-		// Should not happen at all, regex above should catch it
-		panic(fmt.Sprintf("Invalid unit: %v\n", unit))
-	}
-
-	// Hair colour: Six-digit hexadecimal number, prefixed with '#'
-	hairColourMatcher := regexp.MustCompile("^#([0-9a-f]{6})$")
-	match = hairColourMatcher.FindStringSubmatch(passport.hcl)
-	if match == nil {
-		return false
-	}
-
-	// Eye colour: One of (amb, blu, brn, gry, grn, hzl, oth)
-	validEyeColours := map[string]bool{
-		"amb": true,
-		"blu": true,
-		"brn": true,
-		"gry": true,
-		"grn": true,
-		"hzl": true,
-		"oth": true,
-	}
-	_, ok := validEyeColours[passport.ecl]
-	if !ok {
-		return false
-	}
-
-	// Passport ID: Nine-digit number
-	passportIDMatcher := regexp.MustCompile("^([0-9]{9})$")
-	match = passportIDMatcher.FindStringSubmatch(passport.pid)
-	if match == nil {
-		return false
+		value = passport.extras[name]
 	}
 
-	// Country ID is not validated, missing or not
-
-	return true
+	return value, value != ""
 }
 
+var policyPath = flag.String("policy", "", "Validate passports against this YAML or JSON policy file in task two")
+var reportFormat = flag.String("report", "", "Report invalid passports of task two, as 'table' or 'json'")
+
 func main() {
+	flag.Parse()
+
 	taskOne()
 	taskTwo()
 }
@@ -119,8 +77,10 @@ func main() {
 func taskOne() {
 	fmt.Println("== Task one ==")
 
+	passports, err := loadPassports(inputFile)
+	check(err)
+
 	validPassports := 0
-	passports := loadPassports()
 
 	for _, passport := range passports {
 		if passport.requiredFieldsPresent() {
@@ -134,16 +94,25 @@ func taskOne() {
 func taskTwo() {
 	fmt.Println("== Task two ==")
 
-	validPassports := 0
-	passports := loadPassports()
+	policy, err := loadPolicy(*policyPath)
+	check(err)
 
-	for _, passport := range passports {
-		if passport.isValid() {
-			validPassports += 1
-		}
+	passports, err := loadPassports(inputFile)
+	check(err)
+
+	report := newReport(passports, policy)
+
+	switch *reportFormat {
+	case "":
+	case "table":
+		check(report.WriteTable(os.Stdout))
+	case "json":
+		check(report.WriteJSON(os.Stdout))
+	default:
+		check(fmt.Errorf("Invalid report format: %s", *reportFormat))
 	}
 
-	fmt.Printf("Total passports: %d. Valid: %d\n", len(passports), validPassports)
+	fmt.Printf("Total passports: %d. Valid: %d\n", report.Total, report.Valid)
 }
 
 func check(e error) {
@@ -152,49 +121,11 @@ func check(e error) {
 	}
 }
 
-func loadPassports() []Passport {
-	var passports []Passport
-
-	data, err := ioutil.ReadFile(inputFile)
-	// Don't remove trailing new line, as it indicates the end of the last passport entry
-	check(err)
-
-	var passport Passport
-
-	for _, line := range strings.Split(string(data), "\n") {
-		if line == "" {
-			// Individual passports are separated by empty lines
-			passports = append(passports, passport)
-			passport = Passport{}
-		} else {
-			// Non-empty lines contain key:value pairs for current
-			// passport, each separated by a space
-			for _, pair := range strings.Split(line, " ") {
-				kv := strings.Split(pair, ":")
-
-				switch kv[0] {
-				case "byr":
-					passport.byr = kv[1]
-				case "iyr":
-					passport.iyr = kv[1]
-				case "eyr":
-					passport.eyr = kv[1]
-				case "hgt":
-					passport.hgt = kv[1]
-				case "hcl":
-					passport.hcl = kv[1]
-				case "ecl":
-					passport.ecl = kv[1]
-				case "pid":
-					passport.pid = kv[1]
-				case "cid":
-					passport.cid = kv[1]
-				default:
-					panic(fmt.Sprintf("Invalid passport field: %v\n", kv[0]))
-				}
-			}
-		}
+func loadPassports(path string) ([]Passport, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
 	}
 
-	return passports
+	return parsePassports(string(data))
 }
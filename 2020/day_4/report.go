@@ -0,0 +1,107 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"text/tabwriter"
+)
+
+// A passport violating the policy, along with the reasons why.
+type InvalidPassport struct {
+	// Zero-based index of the passport within the input
+	Index int `json:"index"`
+
+	// One-based range of lines in the input which the passport spans
+	FirstLine int `json:"first_line"`
+	LastLine  int `json:"last_line"`
+
+	Failures []Failure `json:"failures"`
+}
+
+// Outcome of validating a list of passports against a policy.
+type Report struct {
+	Total   int               `json:"total"`
+	Valid   int               `json:"valid"`
+	Invalid []InvalidPassport `json:"invalid"`
+
+	// Number of passports failing for each reason, see `Failure.Reason`.
+	FailureCounts map[string]int `json:"failure_counts"`
+}
+
+// Short description of the failure, which is the same for all passports
+// failing the same check. Eg "byr missing" or "hgt units".
+func (failure Failure) Reason() string {
+	if failure.Rule == "required" {
+		return failure.Field + " missing"
+	}
+
+	return failure.Field + " " + failure.Rule
+}
+
+func newReport(passports []Passport, policy Policy) Report {
+	report := Report{
+		Total:         len(passports),
+		Invalid:       []InvalidPassport{},
+		FailureCounts: make(map[string]int),
+	}
+
+	for idx, passport := range passports {
+		failures := policy.Validate(passport)
+		if len(failures) == 0 {
+			report.Valid += 1
+			continue
+		}
+
+		report.Invalid = append(report.Invalid, InvalidPassport{
+			Index:     idx,
+			FirstLine: passport.firstLine,
+			LastLine:  passport.lastLine,
+			Failures:  failures,
+		})
+
+		// A passport may fail the same check more than once - if a
+		// policy lists the same validator twice - but should only be
+		// counted once.
+		reasons := make(map[string]bool)
+		for _, failure := range failures {
+			reasons[failure.Reason()] = true
+		}
+		for reason := range reasons {
+			report.FailureCounts[reason] += 1
+		}
+	}
+
+	return report
+}
+
+// Render the report as a table with one row per failure, followed by the
+// number of passports failing for each reason.
+func (report Report) WriteTable(w io.Writer) error {
+	table := tabwriter.NewWriter(w, 0, 8, 2, ' ', 0)
+
+	fmt.Fprintln(table, "PASSPORT\tLINES\tFIELD\tRULE\tMESSAGE")
+	for _, passport := range report.Invalid {
+		lines := fmt.Sprintf("%d-%d", passport.FirstLine, passport.LastLine)
+
+		for _, failure := range passport.Failures {
+			fmt.Fprintf(table, "%d\t%s\t%s\t%s\t%s\n", passport.Index, lines, failure.Field, failure.Rule, failure.Msg)
+		}
+	}
+
+	fmt.Fprintln(table)
+	fmt.Fprintln(table, "REASON\tPASSPORTS")
+	for _, reason := range sortedKeys(report.FailureCounts) {
+		fmt.Fprintf(table, "%s\t%d\n", reason, report.FailureCounts[reason])
+	}
+
+	return table.Flush()
+}
+
+// Render the report as indented JSON.
+func (report Report) WriteJSON(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+
+	return encoder.Encode(report)
+}
@@ -0,0 +1,94 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func exampleReport(t *testing.T) Report {
+	passports := []Passport{
+		{
+			pid: "087499704", hgt: "74in", ecl: "grn", iyr: "2012", eyr: "2030", byr: "1980", hcl: "#623a2f",
+			firstLine: 1, lastLine: 2,
+		},
+		{
+			eyr: "1972", cid: "100", hcl: "#18171d", ecl: "amb", hgt: "170", pid: "186cm", iyr: "2018", byr: "1926",
+			firstLine: 4, lastLine: 6,
+		},
+		{
+			hcl: "dab227", iyr: "2012", ecl: "brn", hgt: "182cm", pid: "021572410", eyr: "2020",
+			firstLine: 8, lastLine: 10,
+		},
+	}
+
+	return newReport(passports, defaultPolicy(t))
+}
+
+func TestNewReport(t *testing.T) {
+	report := exampleReport(t)
+
+	assert.Equal(t, 3, report.Total)
+	assert.Equal(t, 1, report.Valid)
+
+	assert.Len(t, report.Invalid, 2)
+	assert.Equal(t, 1, report.Invalid[0].Index)
+	assert.Equal(t, 4, report.Invalid[0].FirstLine)
+	assert.Equal(t, 6, report.Invalid[0].LastLine)
+	assert.Len(t, report.Invalid[0].Failures, 3)
+
+	assert.Equal(t, 2, report.Invalid[1].Index)
+	assert.Equal(
+		t,
+		[]Failure{
+			{Field: "byr", Rule: "required", Msg: "missing"},
+			{Field: "hcl", Rule: "regex", Msg: `"dab227" does not match ^#[0-9a-f]{6}$`},
+		},
+		report.Invalid[1].Failures,
+	)
+
+	assert.Equal(
+		t,
+		map[string]int{"byr missing": 1, "eyr range": 1, "hgt units": 1, "pid regex": 1, "hcl regex": 1},
+		report.FailureCounts,
+	)
+}
+
+func TestReportWriteTable(t *testing.T) {
+	var out bytes.Buffer
+	assert.NoError(t, exampleReport(t).WriteTable(&out))
+
+	expected := `PASSPORT  LINES  FIELD  RULE      MESSAGE
+1         4-6    eyr    range     1972 is not within 2020 and 2030
+1         4-6    hgt    units     "170" is not a number followed by a unit
+1         4-6    pid    regex     "186cm" does not match ^[0-9]{9}$
+2         8-10   byr    required  missing
+2         8-10   hcl    regex     "dab227" does not match ^#[0-9a-f]{6}$
+
+REASON       PASSPORTS
+byr missing  1
+eyr range    1
+hcl regex    1
+hgt units    1
+pid regex    1
+`
+	assert.Equal(t, expected, out.String())
+}
+
+func TestReportWriteJSON(t *testing.T) {
+	report := exampleReport(t)
+
+	var out bytes.Buffer
+	assert.NoError(t, report.WriteJSON(&out))
+
+	var decoded Report
+	assert.NoError(t, json.Unmarshal(out.Bytes(), &decoded))
+	assert.Equal(t, report, decoded)
+
+	// Valid passports yield an empty list rather than null
+	var empty bytes.Buffer
+	assert.NoError(t, newReport(nil, defaultPolicy(t)).WriteJSON(&empty))
+	assert.Contains(t, empty.String(), `"invalid": []`)
+}
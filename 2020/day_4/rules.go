@@ -0,0 +1,325 @@
+package main
+
+import (
+	"bytes"
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Policy of task two, used unless another one is given.
+//
+//go:embed policy.yaml
+var defaultPolicyData []byte
+
+// A check of a single field's value.
+type Validator interface {
+	// Short name of the kind of check, eg "range".
+	Name() string
+
+	// Returns nil if the value is valid, or an error describing why not.
+	Validate(value string) error
+}
+
+// Accepts integers within [Min, Max]. If Digits is positive, the value must
+// have exactly that many digits.
+type RangeValidator struct {
+	Min    int `json:"min" yaml:"min"`
+	Max    int `json:"max" yaml:"max"`
+	Digits int `json:"digits,omitempty" yaml:"digits,omitempty"`
+}
+
+func (validator RangeValidator) Name() string {
+	return "range"
+}
+
+func (validator RangeValidator) Validate(value string) error {
+	if !isDigits(value) {
+		return fmt.Errorf("%q is not a number", value)
+	}
+
+	if validator.Digits > 0 && len(value) != validator.Digits {
+		return fmt.Errorf("%q does not have %d digits", value, validator.Digits)
+	}
+
+	x, err := strconv.Atoi(value)
+	if err != nil || x < validator.Min || x > validator.Max {
+		return fmt.Errorf("%s is not within %d and %d", value, validator.Min, validator.Max)
+	}
+
+	return nil
+}
+
+// Accepts values matching a regular expression.
+type RegexValidator struct {
+	pattern *regexp.Regexp
+}
+
+func newRegexValidator(pattern string) (RegexValidator, error) {
+	compiled, err := regexp.Compile(pattern)
+	if err != nil {
+		return RegexValidator{}, err
+	}
+
+	return RegexValidator{pattern: compiled}, nil
+}
+
+func (validator RegexValidator) Name() string {
+	return "regex"
+}
+
+func (validator RegexValidator) Validate(value string) error {
+	if !validator.pattern.MatchString(value) {
+		return fmt.Errorf("%q does not match %s", value, validator.pattern)
+	}
+
+	return nil
+}
+
+// Accepts one of a fixed set of values.
+type EnumValidator struct {
+	values map[string]bool
+}
+
+func newEnumValidator(values []string) EnumValidator {
+	validator := EnumValidator{values: make(map[string]bool)}
+	for _, value := range values {
+		validator.values[value] = true
+	}
+
+	return validator
+}
+
+func (validator EnumValidator) Name() string {
+	return "enum"
+}
+
+func (validator EnumValidator) Validate(value string) error {
+	if !validator.values[value] {
+		return fmt.Errorf("%q is not one of %s", value, strings.Join(sortedKeys(validator.values), ", "))
+	}
+
+	return nil
+}
+
+// Accepts integers followed by a unit, such as "170cm", with a separate range
+// for each unit.
+type UnitRangeValidator struct {
+	Units map[string]RangeValidator
+}
+
+func (validator UnitRangeValidator) Name() string {
+	return "units"
+}
+
+func (validator UnitRangeValidator) Validate(value string) error {
+	split := strings.IndexFunc(value, func(r rune) bool { return r < '0' || r > '9' })
+	if split <= 0 {
+		return fmt.Errorf("%q is not a number followed by a unit", value)
+	}
+
+	number, unit := value[:split], value[split:]
+	unitRange, ok := validator.Units[unit]
+	if !ok {
+		return fmt.Errorf("%q has unknown unit %q, expected one of %s", value, unit, strings.Join(sortedKeys(validator.Units), ", "))
+	}
+
+	if err := unitRange.Validate(number); err != nil {
+		return fmt.Errorf("%s for unit %s", err, unit)
+	}
+
+	return nil
+}
+
+// Required presence and validators of a single passport field.
+type FieldRule struct {
+	Field      string
+	Required   bool
+	Validators []Validator
+}
+
+// Rules which valid passports must adhere to.
+type Policy struct {
+	Rules []FieldRule
+}
+
+// A single reason why a passport is invalid.
+type Failure struct {
+	Field string `json:"field"`
+	// Name of the failed validator, or "required" for missing fields
+	Rule string `json:"rule"`
+	Msg  string `json:"message"`
+}
+
+func (failure Failure) Error() string {
+	return fmt.Sprintf("%s: %s", failure.Field, failure.Msg)
+}
+
+// Validate the passport, returning all the ways in which it violates the
+// policy. Valid passports have no failures.
+//
+// Missing optional fields are not validated.
+func (policy Policy) Validate(passport Passport) []Failure {
+	var failures []Failure
+
+	for _, rule := range policy.Rules {
+		value, ok := passport.Field(rule.Field)
+		if !ok {
+			if rule.Required {
+				failures = append(failures, Failure{Field: rule.Field, Rule: "required", Msg: "missing"})
+			}
+
+			continue
+		}
+
+		for _, validator := range rule.Validators {
+			if err := validator.Validate(value); err != nil {
+				failures = append(failures, Failure{Field: rule.Field, Rule: validator.Name(), Msg: err.Error()})
+			}
+		}
+	}
+
+	return failures
+}
+
+// Whether the passport adheres to the policy.
+func (policy Policy) IsValid(passport Passport) bool {
+	return len(policy.Validate(passport)) == 0
+}
+
+// On-disk format of policies, see `policy.yaml` for an example.
+type policySpec struct {
+	Fields []struct {
+		Name     string     `json:"name" yaml:"name"`
+		Required bool       `json:"required" yaml:"required"`
+		Rules    []ruleSpec `json:"rules" yaml:"rules"`
+	} `json:"fields" yaml:"fields"`
+}
+
+// Exactly one of the fields must be set.
+type ruleSpec struct {
+	Range *RangeValidator           `json:"range" yaml:"range"`
+	Regex *string                   `json:"regex" yaml:"regex"`
+	Enum  []string                  `json:"enum" yaml:"enum"`
+	Units map[string]RangeValidator `json:"units" yaml:"units"`
+}
+
+func (spec ruleSpec) validator() (Validator, error) {
+	var validators []Validator
+
+	if spec.Range != nil {
+		validators = append(validators, *spec.Range)
+	}
+	if spec.Regex != nil {
+		validator, err := newRegexValidator(*spec.Regex)
+		if err != nil {
+			return nil, err
+		}
+		validators = append(validators, validator)
+	}
+	if spec.Enum != nil {
+		validators = append(validators, newEnumValidator(spec.Enum))
+	}
+	if spec.Units != nil {
+		validators = append(validators, UnitRangeValidator{Units: spec.Units})
+	}
+
+	if len(validators) != 1 {
+		return nil, fmt.Errorf("Rule must have exactly one of range, regex, enum or units, got %d", len(validators))
+	}
+
+	return validators[0], nil
+}
+
+// Parse a policy in YAML format, or - if `isJSON` is set - in JSON format.
+func parsePolicy(data []byte, isJSON bool) (Policy, error) {
+	var spec policySpec
+
+	if isJSON {
+		decoder := json.NewDecoder(bytes.NewReader(data))
+		decoder.DisallowUnknownFields()
+		if err := decoder.Decode(&spec); err != nil {
+			return Policy{}, err
+		}
+	} else {
+		decoder := yaml.NewDecoder(bytes.NewReader(data))
+		decoder.KnownFields(true)
+		if err := decoder.Decode(&spec); err != nil {
+			return Policy{}, err
+		}
+	}
+
+	var policy Policy
+	seen := make(map[string]bool)
+
+	for _, field := range spec.Fields {
+		if field.Name == "" {
+			return Policy{}, fmt.Errorf("Field without name")
+		}
+		if seen[field.Name] {
+			return Policy{}, fmt.Errorf("Duplicate field %s", field.Name)
+		}
+		seen[field.Name] = true
+
+		rule := FieldRule{Field: field.Name, Required: field.Required}
+		for idx, ruleSpec := range field.Rules {
+			validator, err := ruleSpec.validator()
+			if err != nil {
+				return Policy{}, fmt.Errorf("Field %s, rule %d: %v", field.Name, idx+1, err)
+			}
+
+			rule.Validators = append(rule.Validators, validator)
+		}
+
+		policy.Rules = append(policy.Rules, rule)
+	}
+
+	return policy, nil
+}
+
+// Load a policy from a YAML or JSON file, depending on its extension. If no
+// path is given, the default policy is returned.
+func loadPolicy(path string) (Policy, error) {
+	if path == "" {
+		return parsePolicy(defaultPolicyData, false)
+	}
+
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return Policy{}, err
+	}
+
+	return parsePolicy(data, strings.EqualFold(filepath.Ext(path), ".json"))
+}
+
+func isDigits(value string) bool {
+	if value == "" {
+		return false
+	}
+
+	for _, r := range value {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+
+	return true
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
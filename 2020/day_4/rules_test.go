@@ -0,0 +1,125 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func defaultPolicy(t *testing.T) Policy {
+	policy, err := loadPolicy("")
+	assert.NoError(t, err)
+
+	return policy
+}
+
+func TestDefaultPolicyValidators(t *testing.T) {
+	policy := defaultPolicy(t)
+
+	validators := make(map[string]Validator)
+	for _, rule := range policy.Rules {
+		for _, validator := range rule.Validators {
+			validators[rule.Field] = validator
+		}
+	}
+
+	valid := map[string][]string{
+		"byr": {"2002"},
+		"hgt": {"60in", "190cm"},
+		"hcl": {"#123abc"},
+		"ecl": {"brn"},
+		"pid": {"000000001"},
+	}
+	invalid := map[string][]string{
+		"byr": {"2003", "02002", "+2002", ""},
+		"hgt": {"190in", "190", "cm", "190mm"},
+		"hcl": {"#123abz", "123abc"},
+		"ecl": {"wat"},
+		"pid": {"0123456789"},
+	}
+
+	for field, values := range valid {
+		for _, value := range values {
+			assert.NoError(t, validators[field].Validate(value), "%s: %s", field, value)
+		}
+	}
+
+	for field, values := range invalid {
+		for _, value := range values {
+			assert.Error(t, validators[field].Validate(value), "%s: %s", field, value)
+		}
+	}
+}
+
+func TestPolicyValidate(t *testing.T) {
+	policy := defaultPolicy(t)
+
+	valid := Passport{
+		pid: "087499704", hgt: "74in", ecl: "grn", iyr: "2012", eyr: "2030", byr: "1980", hcl: "#623a2f",
+	}
+	assert.Empty(t, policy.Validate(valid))
+	assert.True(t, policy.IsValid(valid))
+
+	// All failures are reported, not just the first one
+	invalid := Passport{
+		eyr: "1972", cid: "100", hcl: "#18171d", ecl: "amb", hgt: "170", pid: "186cm", iyr: "2018",
+	}
+	assert.Equal(
+		t,
+		[]Failure{
+			{Field: "byr", Rule: "required", Msg: "missing"},
+			{Field: "eyr", Rule: "range", Msg: "1972 is not within 2020 and 2030"},
+			{Field: "hgt", Rule: "units", Msg: `"170" is not a number followed by a unit`},
+			{Field: "pid", Rule: "regex", Msg: `"186cm" does not match ^[0-9]{9}$`},
+		},
+		policy.Validate(invalid),
+	)
+	assert.False(t, policy.IsValid(invalid))
+}
+
+func TestParsePolicyJSON(t *testing.T) {
+	policy, err := parsePolicy([]byte(`{
+		"fields": [
+			{"name": "hgt", "required": true, "rules": [{"units": {"m": {"min": 1, "max": 2}}}]},
+			{"name": "ecl", "rules": [{"enum": ["red"]}, {"regex": "^r"}]}
+		]
+	}`), true)
+	assert.NoError(t, err)
+
+	assert.True(t, policy.IsValid(Passport{hgt: "2m"}))
+	assert.True(t, policy.IsValid(Passport{hgt: "1m", ecl: "red"}))
+	assert.Equal(
+		t,
+		[]Failure{
+			{Field: "hgt", Rule: "units", Msg: `"3cm" has unknown unit "cm", expected one of m`},
+			{Field: "ecl", Rule: "enum", Msg: `"blue" is not one of red`},
+			{Field: "ecl", Rule: "regex", Msg: `"blue" does not match ^r`},
+		},
+		policy.Validate(Passport{hgt: "3cm", ecl: "blue"}),
+	)
+}
+
+func TestParsePolicyErrors(t *testing.T) {
+	for _, input := range []string{
+		// Invalid regex
+		"fields: [{name: hcl, rules: [{regex: '('}]}]",
+		// Multiple validators in one rule
+		"fields: [{name: hcl, rules: [{regex: 'a', enum: [a]}]}]",
+		// No validator in rule
+		"fields: [{name: hcl, rules: [{}]}]",
+		// Unknown key
+		"fields: [{name: hcl, rules: [{length: 3}]}]",
+		// Duplicate field
+		"fields: [{name: hcl}, {name: hcl}]",
+		"fields: [{required: true}]",
+	} {
+		_, err := parsePolicy([]byte(input), false)
+		assert.Error(t, err, input)
+	}
+
+	_, err := parsePolicy([]byte(`{"fields": [{"name": "hcl", "optional": true}]}`), true)
+	assert.Error(t, err)
+
+	_, err = loadPolicy("does-not-exist.yaml")
+	assert.Error(t, err)
+}
@@ -1,72 +1,66 @@
 package main
 
 import (
+	"flag"
 	"fmt"
 	"io/ioutil"
-	"math"
+	"os"
 	"strings"
 )
 
 const inputFile string = "boarding_pass.input"
 
-// Rows are 0 .. 127 = 2^0 .. 2^7 - 1
-const rowMaxPower int = 6
-
-// Columns are 0 .. 7 = 2^0 .. 2^3 - 1
-const columnMaxPower int = 2
-
-// Each seat has a 10-bit identifier, so the highest seat has ID 1023.
-const maxSeatId int = 1023
-
 type BoardingPass struct {
-	RowSpec    string
-	ColumnSpec string
+	layout PlaneLayout
+	id     int
 }
 
 func (pass BoardingPass) Row() int {
-	return boardingPassPatternToDecimal(pass.RowSpec, rowMaxPower, 'F', 'B')
+	return pass.id >> pass.layout.ColumnBits
 }
 
 func (pass BoardingPass) Column() int {
-	return boardingPassPatternToDecimal(pass.ColumnSpec, columnMaxPower, 'L', 'R')
+	return pass.id & (pass.layout.Columns() - 1)
 }
 
+// Seat ID, which is the seat's row times the number of columns, plus its
+// column. For the default layout that's `row * 8 + column`.
 func (pass BoardingPass) Id() int {
-	return pass.Row()*8 + pass.Column()
+	return pass.id
 }
 
-func boardingPassPatternToDecimal(pattern string, maxPower int, zeroChar rune, oneChar rune) int {
-	id := 0
-	pow := maxPower
-
-	for _, char := range pattern {
-		switch char {
-		case zeroChar:
-		case oneChar:
-			id += int(math.Pow(2, float64(pow)))
-		default:
-			panic(fmt.Sprintf(
-				"Invalid character in specification: '%c' in '%s'\n",
-				char, pattern,
-			))
-		}
-
-		pow -= 1
-	}
-
-	return id
+// Seat code of the pass, eg "FBFBBFFRLR".
+func (pass BoardingPass) Code() string {
+	// IDs of passes are valid by construction
+	code, _ := pass.layout.Encode(pass.id)
+	return code
 }
 
+var rowBits = flag.Int("row-bits", defaultLayout.RowBits, "Number of characters specifying the row in seat codes")
+var columnBits = flag.Int("column-bits", defaultLayout.ColumnBits, "Number of characters specifying the column in seat codes")
+var showMap = flag.Bool("map", false, "Print a map of taken and free seats")
+
 func main() {
-	taskOne()
-	taskTwo()
+	flag.Parse()
+
+	layout := PlaneLayout{RowBits: *rowBits, ColumnBits: *columnBits}
+	check(layout.Validate())
+
+	passes, err := loadBoardingPasses(inputFile, layout)
+	check(err)
+
+	taskOne(passes)
+	taskTwo(layout, passes)
+
+	if *showMap {
+		check(writeSeatMap(os.Stdout, layout, passes))
+	}
 }
 
-func taskOne() {
+func taskOne(passes []BoardingPass) {
 	fmt.Println("== Task one ==")
 
 	maxId := 0
-	passes := loadBoardingPasses()
 	for _, pass := range passes {
 		if id := pass.Id(); id > maxId {
 			maxId = id
@@ -76,28 +70,39 @@ func taskOne() {
 	fmt.Printf("Max ID: %d\n", maxId)
 }
 
-func taskTwo() {
+func taskTwo(layout PlaneLayout, passes []BoardingPass) {
 	fmt.Println("== Task two ==")
 
-	// Track which seats were seen
-	seats := make(map[int]bool)
+	seat, err := findMissingSeat(layout, takenSeats(passes))
+	check(err)
+
+	fmt.Printf("Our seat: %d\n", seat)
+}
 
-	passes := loadBoardingPasses()
+// IDs of the seats for which there is a boarding pass.
+func takenSeats(passes []BoardingPass) map[int]bool {
+	seats := make(map[int]bool)
 	for _, pass := range passes {
 		seats[pass.Id()] = true
 	}
 
+	return seats
+}
+
+// Find our seat, which is the only free one whose neighbours are both taken.
+//
+// Returns an error if there is no such seat.
+func findMissingSeat(layout PlaneLayout, seats map[int]bool) (int, error) {
 	// We are guaranteed to have neither the first nor last seat, so skip
 	// those two entries.
 	// Caching variables below prevent having to check each seat thrice.
-	_, previousSeatSeen := seats[0]
-	_, seatSeen := seats[1]
+	previousSeatSeen := seats[0]
+	seatSeen := seats[1]
 	nextSeatSeen := false
-	for i := 1; i < maxSeatId; i++ {
-		_, nextSeatSeen = seats[i+1]
+	for i := 1; i < layout.Seats()-1; i++ {
+		nextSeatSeen = seats[i+1]
 		if previousSeatSeen && nextSeatSeen && !seatSeen {
-			fmt.Printf("Our seat: %d\n", i)
-			return
+			return i, nil
 		}
 
 		// Our seat not seen, update caching variables
@@ -105,6 +110,7 @@ func taskTwo() {
 		seatSeen = nextSeatSeen
 	}
 
+	return 0, fmt.Errorf("No free seat between two taken ones")
 }
 
 func check(e error) {
@@ -113,25 +119,30 @@ func check(e error) {
 	}
 }
 
-func loadBoardingPasses() []BoardingPass {
+// Load one seat code per line, ignoring blank lines.
+//
+// Returns an error, with the offending line, on invalid seat codes.
+func loadBoardingPasses(path string, layout PlaneLayout) ([]BoardingPass, error) {
 	var passes []BoardingPass
 
-	data, err := ioutil.ReadFile(inputFile)
-	check(err)
-	// Remove trailing newline
-	data = data[:len(data)-1]
-
-	for _, line := range strings.Split(string(data), "\n") {
-		// Indices 0..6 are row specification, 7..9 column
-		// specification.
-		// Mind that Go's sub-slicing is a half-open interval,
-		// including the start but excluding the stop index.
-
-		passes = append(
-			passes,
-			BoardingPass{RowSpec: line[:7], ColumnSpec: line[7:]},
-		)
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	for idx, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+
+		pass, err := layout.Decode(line)
+		if err != nil {
+			return nil, fmt.Errorf("Line %d: %v", idx+1, err)
+		}
+
+		passes = append(passes, pass)
 	}
 
-	return passes
+	return passes, nil
 }
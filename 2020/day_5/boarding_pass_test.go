@@ -0,0 +1,32 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFindMissingSeat(t *testing.T) {
+	seat, err := findMissingSeat(defaultLayout, map[int]bool{3: true, 4: true, 6: true, 7: true, 9: true})
+	assert.NoError(t, err)
+	assert.Equal(t, 5, seat)
+
+	_, err = findMissingSeat(defaultLayout, map[int]bool{3: true, 4: true})
+	assert.Error(t, err)
+}
+
+func TestLoadBoardingPasses(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "passes.input")
+
+	assert.NoError(t, os.WriteFile(path, []byte("FBFBBFFRLR\r\n\nBFFFBBFRRR"), 0644))
+	passes, err := loadBoardingPasses(path, defaultLayout)
+	assert.NoError(t, err)
+	assert.Len(t, passes, 2)
+	assert.Equal(t, 567, passes[1].Id())
+
+	assert.NoError(t, os.WriteFile(path, []byte("FBFBBFFRLR\nBFFFBB\n"), 0644))
+	_, err = loadBoardingPasses(path, defaultLayout)
+	assert.EqualError(t, err, `Line 2: Seat code "BFFFBB" has length 6, expected 10`)
+}
@@ -0,0 +1,147 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Layout of a plane, with 2^RowBits rows of 2^ColumnBits seats each.
+//
+// Seat codes specify the row in binary using 'F' (front) for 0 and 'B' (back)
+// for 1, followed by the column using 'L' (left) for 0 and 'R' (right) for 1.
+// Taken as a whole, a code is thus the seat's ID in binary.
+type PlaneLayout struct {
+	RowBits    int
+	ColumnBits int
+}
+
+// 128 rows of 8 seats each.
+var defaultLayout = PlaneLayout{RowBits: 7, ColumnBits: 3}
+
+// Returns an error if the layout has no seats, or is too large to be handled.
+func (layout PlaneLayout) Validate() error {
+	if layout.RowBits < 1 || layout.ColumnBits < 1 || layout.RowBits+layout.ColumnBits > 30 {
+		return fmt.Errorf("Invalid layout with %d row and %d column bits", layout.RowBits, layout.ColumnBits)
+	}
+
+	return nil
+}
+
+func (layout PlaneLayout) Rows() int {
+	return 1 << layout.RowBits
+}
+
+func (layout PlaneLayout) Columns() int {
+	return 1 << layout.ColumnBits
+}
+
+// Number of seats, and hence one more than the highest seat ID.
+func (layout PlaneLayout) Seats() int {
+	return layout.Rows() * layout.Columns()
+}
+
+// Length of seat codes.
+func (layout PlaneLayout) CodeLength() int {
+	return layout.RowBits + layout.ColumnBits
+}
+
+// Decode a seat code such as "FBFBBFFRLR".
+//
+// Returns an error if the code has the wrong length, or if it contains
+// invalid characters.
+func (layout PlaneLayout) Decode(code string) (BoardingPass, error) {
+	if len(code) != layout.CodeLength() {
+		return BoardingPass{}, fmt.Errorf("Seat code %q has length %d, expected %d", code, len(code), layout.CodeLength())
+	}
+
+	id := 0
+	for idx := 0; idx < len(code); idx += 1 {
+		zero, one := layout.symbols(idx)
+
+		id <<= 1
+		switch code[idx] {
+		case zero:
+		case one:
+			id |= 1
+		default:
+			return BoardingPass{}, fmt.Errorf(
+				"Invalid character '%c' at position %d of seat code %q, expected '%c' or '%c'",
+				code[idx], idx+1, code, zero, one,
+			)
+		}
+	}
+
+	return BoardingPass{layout: layout, id: id}, nil
+}
+
+// Encode a seat ID as seat code, ie the reverse of `Decode`.
+//
+// Returns an error if there is no seat with that ID.
+func (layout PlaneLayout) Encode(id int) (string, error) {
+	if id < 0 || id >= layout.Seats() {
+		return "", fmt.Errorf("Seat ID %d out of range 0 .. %d", id, layout.Seats()-1)
+	}
+
+	code := make([]byte, layout.CodeLength())
+	for idx := len(code) - 1; idx >= 0; idx -= 1 {
+		zero, one := layout.symbols(idx)
+
+		if id&1 == 1 {
+			code[idx] = one
+		} else {
+			code[idx] = zero
+		}
+		id >>= 1
+	}
+
+	return string(code), nil
+}
+
+// Characters representing 0 and 1 at the given position of a seat code.
+func (layout PlaneLayout) symbols(idx int) (byte, byte) {
+	if idx < layout.RowBits {
+		return 'F', 'B'
+	}
+
+	return 'L', 'R'
+}
+
+// Render a map of the plane, with one line per row and the aisle in the
+// middle. Taken seats are marked '#', free ones '.', and our seat - as found
+// by `findMissingSeat` - 'X'.
+func writeSeatMap(w io.Writer, layout PlaneLayout, passes []BoardingPass) error {
+	taken := takenSeats(passes)
+	ours, err := findMissingSeat(layout, taken)
+	if err != nil {
+		ours = -1
+	}
+
+	var out strings.Builder
+	rowWidth := len(fmt.Sprint(layout.Rows() - 1))
+
+	for row := 0; row < layout.Rows(); row += 1 {
+		fmt.Fprintf(&out, "%*d ", rowWidth, row)
+
+		for column := 0; column < layout.Columns(); column += 1 {
+			if column == layout.Columns()/2 {
+				out.WriteByte(' ')
+			}
+
+			id := row*layout.Columns() + column
+			switch {
+			case id == ours:
+				out.WriteByte('X')
+			case taken[id]:
+				out.WriteByte('#')
+			default:
+				out.WriteByte('.')
+			}
+		}
+
+		out.WriteByte('\n')
+	}
+
+	_, err = io.WriteString(w, out.String())
+	return err
+}
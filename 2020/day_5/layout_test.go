@@ -0,0 +1,101 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDecode(t *testing.T) {
+	for code, expected := range map[string][3]int{
+		"FBFBBFFRLR": {44, 5, 357},
+		"BFFFBBFRRR": {70, 7, 567},
+		"FFFBBBFRRR": {14, 7, 119},
+		"BBFFBBFRLL": {102, 4, 820},
+	} {
+		pass, err := defaultLayout.Decode(code)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, [3]int{pass.Row(), pass.Column(), pass.Id()}, code)
+		assert.Equal(t, code, pass.Code())
+	}
+}
+
+func TestDecodeErrors(t *testing.T) {
+	_, err := defaultLayout.Decode("FBFBBFFRL")
+	assert.EqualError(t, err, `Seat code "FBFBBFFRL" has length 9, expected 10`)
+
+	_, err = defaultLayout.Decode("FBFBBFFRLRR")
+	assert.Error(t, err)
+
+	// Column characters within the row specification and vice versa
+	_, err = defaultLayout.Decode("FBFBBFRRLR")
+	assert.EqualError(t, err, `Invalid character 'R' at position 7 of seat code "FBFBBFRRLR", expected 'F' or 'B'`)
+
+	_, err = defaultLayout.Decode("FBFBBFFRLF")
+	assert.Error(t, err)
+
+	_, err = defaultLayout.Decode("fbfbbffrlr")
+	assert.Error(t, err)
+}
+
+func TestEncode(t *testing.T) {
+	code, err := defaultLayout.Encode(357)
+	assert.NoError(t, err)
+	assert.Equal(t, "FBFBBFFRLR", code)
+
+	code, err = defaultLayout.Encode(0)
+	assert.NoError(t, err)
+	assert.Equal(t, "FFFFFFFLLL", code)
+
+	code, err = defaultLayout.Encode(1023)
+	assert.NoError(t, err)
+	assert.Equal(t, "BBBBBBBRRR", code)
+
+	_, err = defaultLayout.Encode(1024)
+	assert.Error(t, err)
+	_, err = defaultLayout.Encode(-1)
+	assert.Error(t, err)
+
+	// Round trip for all seats
+	for id := 0; id < defaultLayout.Seats(); id += 1 {
+		code, err := defaultLayout.Encode(id)
+		assert.NoError(t, err)
+
+		pass, err := defaultLayout.Decode(code)
+		assert.NoError(t, err)
+		assert.Equal(t, id, pass.Id())
+	}
+}
+
+func TestCustomLayout(t *testing.T) {
+	layout := PlaneLayout{RowBits: 2, ColumnBits: 1}
+	assert.NoError(t, layout.Validate())
+	assert.Equal(t, 4, layout.Rows())
+	assert.Equal(t, 2, layout.Columns())
+	assert.Equal(t, 3, layout.CodeLength())
+
+	pass, err := layout.Decode("BFR")
+	assert.NoError(t, err)
+	assert.Equal(t, 2, pass.Row())
+	assert.Equal(t, 1, pass.Column())
+	assert.Equal(t, 5, pass.Id())
+
+	assert.Error(t, PlaneLayout{RowBits: 0, ColumnBits: 3}.Validate())
+	assert.Error(t, PlaneLayout{RowBits: 20, ColumnBits: 20}.Validate())
+}
+
+func TestWriteSeatMap(t *testing.T) {
+	layout := PlaneLayout{RowBits: 2, ColumnBits: 2}
+
+	var passes []BoardingPass
+	for _, code := range []string{"FBLR", "FBRL", "FBRR", "BFLL", "BFRL"} {
+		pass, err := layout.Decode(code)
+		assert.NoError(t, err)
+		passes = append(passes, pass)
+	}
+
+	var out bytes.Buffer
+	assert.NoError(t, writeSeatMap(&out, layout, passes))
+	assert.Equal(t, "0 .. ..\n1 .# ##\n2 #X #.\n3 .. ..\n", out.String())
+}
@@ -1,55 +1,55 @@
 package main
 
 import (
+	"flag"
 	"fmt"
 	"io/ioutil"
-	"strings"
 )
 
 const inputFile string = "customs.input"
 
+var exactly = flag.Int("exactly", 0, "Also count questions answered 'yes' by exactly this many people per group")
+
 func main() {
-	taskOne()
-	taskTwo()
+	flag.Parse()
+
+	groups, err := loadGroups(inputFile)
+	check(err)
+
+	taskOne(groups)
+	taskTwo(groups)
+
+	if *exactly > 0 {
+		fmt.Printf("== Answered by exactly %d ==\n", *exactly)
+
+		total := 0
+		for _, group := range groups {
+			total += len(group.AnsweredByExactly(*exactly))
+		}
+
+		fmt.Printf("Total yes counts (grouped by group, exactly %d of the members must have said yes): %d\n", *exactly, total)
+	}
 }
 
-func taskOne() {
+func taskOne(groups []Group) {
 	fmt.Println("== Task one ==")
 
-	answers := loadAnswers()
+	// We care about the total yes counts *per group*, ie two 'yes' counts
+	// for answer 'x' within the same group only count once.
 	totalYesCounts := 0
-	for _, group := range answers {
-		// We care about the total yes counts *per group*, ie two 'yes'
-		// counts for answer 'x' within the same group only count once.
-		// This is equivalent to the number of keys within the map
-		// minus the key which is used to track the number of people in
-		// the group.
-		totalYesCounts += len(group) - 1
+	for _, group := range groups {
+		totalYesCounts += len(group.Union())
 	}
 
 	fmt.Printf("Total yes counts (grouped by group): %d\n", totalYesCounts)
 }
 
-func taskTwo() {
+func taskTwo(groups []Group) {
 	fmt.Println("== Task two ==")
 
-	answers := loadAnswers()
 	totalYesCounts := 0
-	for _, group := range answers {
-		memberCount, ok := group['_']
-		// This count must be present
-		if !ok {
-			panic(fmt.Sprintf("Group member count not present for group: %+v\n", group))
-		}
-
-		for answer, yesCount := range group {
-			// Ignore the meta group-size key
-			if answer != '_' {
-				if memberCount == yesCount {
-					totalYesCounts += 1
-				}
-			}
-		}
+	for _, group := range groups {
+		totalYesCounts += len(group.Intersection())
 	}
 
 	fmt.Printf("Total yes counts (grouped by group, all members must have said yes): %d\n", totalYesCounts)
@@ -61,43 +61,11 @@ func check(e error) {
 	}
 }
 
-// Prepare per-group count of how many people answered 'yes' to each question.
-//
-// Questions to which none answered are not contained in the inner map. Further
-// each map contains a '_' key which stores the number of members the group
-// has.
-//
-// As an example, consider the following data structure:
-// [
-//   { '_': 3, 'a': 2, 'b': 1 },
-//   { '_': 5, 'a': 5, 'c': 2 },
-// ]
-// This contains data of two groups, one with three members where 2 voted for
-// 'a', one for 'b', and another group with five members where 5 voted for 'a', two
-// for 'c'.
-func loadAnswers() []map[rune]int {
-	var answers []map[rune]int
-
-	data, err := ioutil.ReadFile(inputFile)
-	check(err)
-	// Keep trailingn newline, as those separate groups of answers
-
-	group := make(map[rune]int)
-	for _, line := range strings.Split(string(data), "\n") {
-		// Empty lines separate individual groups
-		if line == "" {
-			answers = append(answers, group)
-			group = make(map[rune]int)
-		} else {
-			// Each line is the list of one-char answers the person
-			// answered 'yes' to, eg 'abjz'.
-			for _, answer := range line {
-				group[answer] += 1
-			}
-			// And each line itself is the answers of one person
-			group['_'] += 1
-		}
+func loadGroups(path string) ([]Group, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
 	}
 
-	return answers
+	return parseGroups(string(data)), nil
 }
@@ -0,0 +1,172 @@
+package main
+
+import (
+	"sort"
+	"strings"
+)
+
+// Set of questions answered with 'yes'.
+type AnswerSet map[rune]bool
+
+func newAnswerSet(answers string) AnswerSet {
+	set := make(AnswerSet)
+	for _, answer := range answers {
+		set[answer] = true
+	}
+
+	return set
+}
+
+// Questions answered by either set.
+func (set AnswerSet) Union(other AnswerSet) AnswerSet {
+	union := make(AnswerSet)
+	for answer := range set {
+		union[answer] = true
+	}
+	for answer := range other {
+		union[answer] = true
+	}
+
+	return union
+}
+
+// Questions answered by both sets.
+func (set AnswerSet) Intersection(other AnswerSet) AnswerSet {
+	intersection := make(AnswerSet)
+	for answer := range set {
+		if other[answer] {
+			intersection[answer] = true
+		}
+	}
+
+	return intersection
+}
+
+// Questions answered by exactly one of the sets.
+func (set AnswerSet) SymmetricDifference(other AnswerSet) AnswerSet {
+	difference := make(AnswerSet)
+	for answer := range set {
+		if !other[answer] {
+			difference[answer] = true
+		}
+	}
+	for answer := range other {
+		if !set[answer] {
+			difference[answer] = true
+		}
+	}
+
+	return difference
+}
+
+// Answers in ascending order, eg "abc".
+func (set AnswerSet) String() string {
+	answers := make([]rune, 0, len(set))
+	for answer := range set {
+		answers = append(answers, answer)
+	}
+	sort.Slice(answers, func(i, j int) bool { return answers[i] < answers[j] })
+
+	return string(answers)
+}
+
+// Group of people travelling together, with the answers of each of them.
+type Group struct {
+	members []AnswerSet
+}
+
+// Number of people in the group.
+func (group Group) Size() int {
+	return len(group.members)
+}
+
+// Answers of each person, in the order they appear in the input.
+func (group Group) Members() []AnswerSet {
+	return group.members
+}
+
+// Questions answered by anyone in the group.
+func (group Group) Union() AnswerSet {
+	union := make(AnswerSet)
+	for _, member := range group.members {
+		union = union.Union(member)
+	}
+
+	return union
+}
+
+// Questions answered by everyone in the group. Empty for empty groups.
+func (group Group) Intersection() AnswerSet {
+	if len(group.members) == 0 {
+		return make(AnswerSet)
+	}
+
+	intersection := group.members[0]
+	for _, member := range group.members[1:] {
+		intersection = intersection.Intersection(member)
+	}
+
+	// Copy, as single-member groups would otherwise return the member's
+	// own answers.
+	return intersection.Union(nil)
+}
+
+// Symmetric difference of all members' answers, ie questions answered by an
+// odd number of people in the group.
+func (group Group) SymmetricDifference() AnswerSet {
+	difference := make(AnswerSet)
+	for _, member := range group.members {
+		difference = difference.SymmetricDifference(member)
+	}
+
+	return difference
+}
+
+// Questions answered by exactly `k` people in the group.
+func (group Group) AnsweredByExactly(k int) AnswerSet {
+	counts := make(map[rune]int)
+	for _, member := range group.members {
+		for answer := range member {
+			counts[answer] += 1
+		}
+	}
+
+	answered := make(AnswerSet)
+	for answer, count := range counts {
+		if count == k {
+			answered[answer] = true
+		}
+	}
+
+	return answered
+}
+
+// Parse groups of answers. Each line holds the one-char answers one person
+// answered 'yes' to, eg "abjz", with groups separated by blank lines.
+//
+// Both LF and CRLF line endings are accepted, and the last group need not be
+// followed by a blank line.
+func parseGroups(input string) []Group {
+	var groups []Group
+
+	var group Group
+	for _, line := range strings.Split(input, "\n") {
+		line = strings.TrimSpace(line)
+
+		// Blank lines separate individual groups
+		if line == "" {
+			if group.Size() > 0 {
+				groups = append(groups, group)
+				group = Group{}
+			}
+		} else {
+			group.members = append(group.members, newAnswerSet(line))
+		}
+	}
+
+	if group.Size() > 0 {
+		groups = append(groups, group)
+	}
+
+	return groups
+}
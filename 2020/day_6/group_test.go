@@ -0,0 +1,80 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func exampleGroups(t *testing.T) []Group {
+	groups, err := loadGroups("customs.input.test1")
+	assert.NoError(t, err)
+
+	return groups
+}
+
+func TestParseGroups(t *testing.T) {
+	// The example lacks a trailing newline
+	groups := exampleGroups(t)
+	assert.Len(t, groups, 5)
+	assert.Equal(t, []int{1, 3, 2, 4, 1}, []int{groups[0].Size(), groups[1].Size(), groups[2].Size(), groups[3].Size(), groups[4].Size()})
+
+	groups = parseGroups("ab\r\n_c\r\n\r\n\r\n\r\nd\r\n")
+	assert.Len(t, groups, 2)
+	assert.Equal(t, []AnswerSet{newAnswerSet("ab"), newAnswerSet("_c")}, groups[0].Members())
+	assert.Equal(t, []AnswerSet{newAnswerSet("d")}, groups[1].Members())
+
+	assert.Empty(t, parseGroups("\n\n"))
+}
+
+func TestGroupSetAlgebra(t *testing.T) {
+	groups := exampleGroups(t)
+
+	var unions, intersections, differences, exactlyOnes []string
+	for _, group := range groups {
+		unions = append(unions, group.Union().String())
+		intersections = append(intersections, group.Intersection().String())
+		differences = append(differences, group.SymmetricDifference().String())
+		exactlyOnes = append(exactlyOnes, group.AnsweredByExactly(1).String())
+	}
+
+	assert.Equal(t, []string{"abc", "abc", "abc", "a", "b"}, unions)
+	assert.Equal(t, []string{"abc", "", "a", "a", "b"}, intersections)
+	assert.Equal(t, []string{"abc", "abc", "bc", "", "b"}, differences)
+	assert.Equal(t, []string{"abc", "abc", "bc", "", "b"}, exactlyOnes)
+
+	assert.Equal(t, "a", groups[3].AnsweredByExactly(4).String())
+	assert.Equal(t, "", groups[3].AnsweredByExactly(3).String())
+}
+
+func TestGroupAnswerUnderscore(t *testing.T) {
+	// '_' is an answer like any other
+	group := parseGroups("_a\n_\n")[0]
+	assert.Equal(t, 2, group.Size())
+	assert.Equal(t, "_a", group.Union().String())
+	assert.Equal(t, "_", group.Intersection().String())
+}
+
+func TestEmptyGroup(t *testing.T) {
+	var group Group
+	assert.Empty(t, group.Union())
+	assert.Empty(t, group.Intersection())
+	assert.Empty(t, group.SymmetricDifference())
+}
+
+func TestIntersectionIsCopy(t *testing.T) {
+	group := parseGroups("ab\n")[0]
+
+	intersection := group.Intersection()
+	intersection['z'] = true
+	assert.Equal(t, "ab", group.Members()[0].String())
+}
+
+func TestAnswerSet(t *testing.T) {
+	a := newAnswerSet("abc")
+	b := newAnswerSet("bcd")
+
+	assert.Equal(t, "abcd", a.Union(b).String())
+	assert.Equal(t, "bc", a.Intersection(b).String())
+	assert.Equal(t, "ad", a.SymmetricDifference(b).String())
+}
@@ -0,0 +1,102 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Which relation to follow when highlighting part of the graph.
+type Direction int
+
+const (
+	// Highlight all colours which a colour must contain
+	Contains Direction = iota
+	// Highlight all colours which may contain a colour
+	ContainedBy
+)
+
+func parseDirection(s string) (Direction, error) {
+	switch s {
+	case "contains":
+		return Contains, nil
+	case "contained-by":
+		return ContainedBy, nil
+	default:
+		return Contains, fmt.Errorf("Invalid direction '%s', expected 'contains' or 'contained-by'", s)
+	}
+}
+
+// Write the graph in Graphviz' DOT format, with edges pointing from each
+// colour to the colours it must contain, labelled with the count.
+//
+// If `highlight` is non-empty, the subgraph reachable from that colour in the
+// given direction is highlighted, with the colour itself being emphasized
+// further. Everything else is greyed out.
+func writeDOT(w io.Writer, graph *BagGraph, highlight string, direction Direction) error {
+	var reachable map[string]bool
+	if highlight != "" {
+		if !graph.HasColour(highlight) {
+			return fmt.Errorf("Unknown colour: %s", highlight)
+		}
+
+		if direction == Contains {
+			reachable = graph.Descendants(highlight)
+		} else {
+			reachable = graph.Ancestors(highlight)
+		}
+		reachable[highlight] = true
+	}
+
+	var out strings.Builder
+
+	fmt.Fprintf(&out, "digraph luggage {\n")
+	fmt.Fprintf(&out, "  node [shape=box, style=rounded];\n")
+
+	for _, colour := range graph.Colours() {
+		attributes := ""
+		switch {
+		case colour == highlight:
+			attributes = " [style=\"rounded,filled,bold\", fillcolor=gold]"
+		case highlight != "" && reachable[colour]:
+			attributes = " [style=\"rounded,bold\"]"
+		case highlight != "":
+			attributes = " [color=grey, fontcolor=grey]"
+		}
+
+		fmt.Fprintf(&out, "  %s%s;\n", quoteDOT(colour), attributes)
+	}
+
+	for _, colour := range graph.Colours() {
+		children := graph.Children(colour)
+		for _, child := range sortedKeys(children) {
+			attributes := ""
+			if highlight != "" {
+				// As the subgraph contains everything reachable
+				// from the highlighted colour, it contains
+				// exactly those edges with both ends in it.
+				if reachable[colour] && reachable[child] {
+					attributes = ", penwidth=2"
+				} else {
+					attributes = ", color=grey, fontcolor=grey"
+				}
+			}
+
+			fmt.Fprintf(
+				&out,
+				"  %s -> %s [label=\"%d\"%s];\n",
+				quoteDOT(colour), quoteDOT(child), children[child], attributes,
+			)
+		}
+	}
+
+	fmt.Fprintf(&out, "}\n")
+
+	_, err := io.WriteString(w, out.String())
+	return err
+}
+
+// Quote an identifier for use in DOT files.
+func quoteDOT(id string) string {
+	return "\"" + strings.ReplaceAll(id, "\"", "\\\"") + "\""
+}
@@ -0,0 +1,67 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWriteDOT(t *testing.T) {
+	graph := newBagGraph(exampleRules())
+
+	var buf bytes.Buffer
+	assert.NoError(t, writeDOT(&buf, graph, "", Contains))
+	dot := buf.String()
+
+	assert.True(t, strings.HasPrefix(dot, "digraph luggage {\n"))
+	assert.True(t, strings.HasSuffix(dot, "}\n"))
+	assert.Contains(t, dot, "  \"muted yellow\" -> \"faded blue\" [label=\"9\"];\n")
+	assert.Equal(t, 13, strings.Count(dot, "->"))
+	assert.NotContains(t, dot, "grey")
+}
+
+func TestWriteDOTHighlight(t *testing.T) {
+	graph := newBagGraph(exampleRules())
+
+	{
+		var buf bytes.Buffer
+		assert.NoError(t, writeDOT(&buf, graph, "shiny gold", Contains))
+		dot := buf.String()
+
+		assert.Contains(t, dot, "  \"shiny gold\" [style=\"rounded,filled,bold\", fillcolor=gold];\n")
+		assert.Contains(t, dot, "  \"dark olive\" [style=\"rounded,bold\"];\n")
+		assert.Contains(t, dot, "  \"light red\" [color=grey, fontcolor=grey];\n")
+		assert.Contains(t, dot, "  \"shiny gold\" -> \"dark olive\" [label=\"1\", penwidth=2];\n")
+		// Faded blue is contained in shiny gold, but not via muted
+		// yellow
+		assert.Contains(t, dot, "  \"muted yellow\" -> \"faded blue\" [label=\"9\", color=grey, fontcolor=grey];\n")
+		assert.Equal(t, 6, strings.Count(dot, "penwidth=2"))
+	}
+
+	{
+		var buf bytes.Buffer
+		assert.NoError(t, writeDOT(&buf, graph, "shiny gold", ContainedBy))
+		dot := buf.String()
+
+		assert.Contains(t, dot, "  \"light red\" [style=\"rounded,bold\"];\n")
+		assert.Contains(t, dot, "  \"dark olive\" [color=grey, fontcolor=grey];\n")
+		assert.Contains(t, dot, "  \"bright white\" -> \"shiny gold\" [label=\"1\", penwidth=2];\n")
+		assert.Equal(t, 6, strings.Count(dot, "penwidth=2"))
+	}
+
+	{
+		var buf bytes.Buffer
+		assert.Error(t, writeDOT(&buf, graph, "plaid purple", Contains))
+	}
+}
+
+func TestParseDirection(t *testing.T) {
+	direction, err := parseDirection("contained-by")
+	assert.NoError(t, err)
+	assert.Equal(t, ContainedBy, direction)
+
+	_, err = parseDirection("sideways")
+	assert.Error(t, err)
+}
@@ -0,0 +1,308 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Directed graph of bag rules, with an edge from each colour to every colour
+// it must contain, weighted by the number of bags it must contain.
+type BagGraph struct {
+	// For each colour, the colours it must contain, and how many of each.
+	contains map[string]map[string]int
+	// For each colour, the colours which must contain it, and how many of
+	// it.
+	containedIn map[string]map[string]int
+
+	// Memoized results of `Contents`, for each colour.
+	contents map[string]map[string]int
+}
+
+// Build a graph of the rules returned by `loadRules`.
+//
+// Colours which only ever appear as contents of other bags, but have no rule
+// of their own, are assumed to contain nothing.
+func newBagGraph(rules map[string]map[string]int) *BagGraph {
+	graph := BagGraph{
+		contains:    make(map[string]map[string]int),
+		containedIn: invertRules(rules),
+		contents:    make(map[string]map[string]int),
+	}
+
+	for colour, children := range rules {
+		graph.contains[colour] = make(map[string]int)
+		for child, count := range children {
+			graph.contains[colour][child] = count
+		}
+	}
+
+	for colour := range graph.containedIn {
+		if _, ok := graph.contains[colour]; !ok {
+			graph.contains[colour] = make(map[string]int)
+		}
+	}
+
+	return &graph
+}
+
+// Returned if an operation can't be performed due to the rules being cyclic,
+// ie some bag having to - eventually - contain itself.
+type CycleError struct {
+	// Colours forming the cycle, with the first colour repeated at the end.
+	// Each colour must directly contain the following one.
+	Cycle []string
+}
+
+func (err CycleError) Error() string {
+	return fmt.Sprintf("Cyclic bag rules: %s", strings.Join(err.Cycle, " -> "))
+}
+
+// All colours in the graph, in alphabetical order.
+func (graph *BagGraph) Colours() []string {
+	return sortedKeys(graph.contains)
+}
+
+// Whether the graph contains the given colour.
+func (graph *BagGraph) HasColour(colour string) bool {
+	_, ok := graph.contains[colour]
+	return ok
+}
+
+// Colours which a bag of the given colour must directly contain, and how many
+// of each. The result is a copy, so may be modified freely.
+func (graph *BagGraph) Children(colour string) map[string]int {
+	return copyCounts(graph.contains[colour])
+}
+
+// Colours which must directly contain a bag of the given colour, and how many
+// of it. The result is a copy, so may be modified freely.
+func (graph *BagGraph) Parents(colour string) map[string]int {
+	return copyCounts(graph.containedIn[colour])
+}
+
+// Return number and type of bags which a bag of the given colour must
+// contain, including bags inside of bags.
+//
+// Results are memoized, so each colour's contents are only calculated once.
+// The result is a copy of the memoized one, so may be modified freely.
+// Returns an error if the colour is unknown, or if its contents are infinite
+// due to cyclic rules.
+func (graph *BagGraph) Contents(colour string) (map[string]int, error) {
+	contents, err := graph.memoizedContents(colour)
+	if err != nil {
+		return nil, err
+	}
+
+	return copyCounts(contents), nil
+}
+
+// Like `Contents`, but returning the memoized map itself, which must not be
+// modified.
+func (graph *BagGraph) memoizedContents(colour string) (map[string]int, error) {
+	if !graph.HasColour(colour) {
+		return nil, fmt.Errorf("No information which bags a bag of colour %s must contain", colour)
+	}
+
+	return graph.contentsRecurse(colour, nil)
+}
+
+// This is the recursion body of `Contents`, not to be used directly. `path`
+// holds the colours whose contents are currently being calculated, in order
+// to detect cycles. The returned map is the memoized one, and must not be
+// modified.
+func (graph *BagGraph) contentsRecurse(colour string, path []string) (map[string]int, error) {
+	if contents, ok := graph.contents[colour]; ok {
+		return contents, nil
+	}
+
+	for idx, pathColour := range path {
+		if pathColour == colour {
+			cycle := append(append([]string{}, path[idx:]...), colour)
+			return nil, CycleError{Cycle: cycle}
+		}
+	}
+	path = append(path, colour)
+
+	contents := make(map[string]int)
+	for _, child := range sortedKeys(graph.contains[colour]) {
+		count := graph.contains[colour][child]
+		contents[child] += count
+
+		innerContents, err := graph.contentsRecurse(child, path)
+		if err != nil {
+			return nil, err
+		}
+
+		for innerColour, innerCount := range innerContents {
+			contents[innerColour] += count * innerCount
+		}
+	}
+
+	graph.contents[colour] = contents
+	return contents, nil
+}
+
+// Total number of bags which a bag of the given colour must contain.
+//
+// See `Contents` for details.
+func (graph *BagGraph) TotalContents(colour string) (int, error) {
+	contents, err := graph.memoizedContents(colour)
+	if err != nil {
+		return 0, err
+	}
+
+	total := 0
+	for _, count := range contents {
+		total += count
+	}
+
+	return total, nil
+}
+
+// Return the colours of bags which may - directly or indirectly - contain a
+// bag of the given colour.
+//
+// Unlike `Contents`, this is well-defined for cyclic rules too. A colour which
+// is part of a cycle is its own ancestor.
+func (graph *BagGraph) Ancestors(colour string) map[string]bool {
+	ancestors := make(map[string]bool)
+
+	queue := []string{colour}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+
+		for parent := range graph.containedIn[current] {
+			// Skipping known ancestors is, to some extent, to
+			// optimize, but also to protect against loops.
+			if !ancestors[parent] {
+				ancestors[parent] = true
+				queue = append(queue, parent)
+			}
+		}
+	}
+
+	return ancestors
+}
+
+// Return the colours of bags which a bag of the given colour must - directly
+// or indirectly - contain.
+//
+// Like `Ancestors`, this is well-defined for cyclic rules too.
+func (graph *BagGraph) Descendants(colour string) map[string]bool {
+	descendants := make(map[string]bool)
+
+	queue := []string{colour}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+
+		for child := range graph.contains[current] {
+			if !descendants[child] {
+				descendants[child] = true
+				queue = append(queue, child)
+			}
+		}
+	}
+
+	return descendants
+}
+
+// Find a cycle in the rules, ie a bag which must eventually contain itself.
+//
+// Returns nil if the rules are acyclic. Otherwise returns the colours forming
+// the cycle, in the format described in `CycleError`.
+func (graph *BagGraph) FindCycle() []string {
+	_, err := graph.TopologicalOrder()
+	if cycleErr, ok := err.(CycleError); ok {
+		return cycleErr.Cycle
+	}
+
+	return nil
+}
+
+// Order all colours such that each colour comes before all colours it must
+// contain. The order is deterministic, irrespective of map iteration order.
+//
+// Returns a `CycleError` if no such order exists due to cyclic rules.
+func (graph *BagGraph) TopologicalOrder() ([]string, error) {
+	// Depth-first search, with colours being appended to the order once all
+	// their contents are. Reversing this yields a topological order.
+	const (
+		unvisited = iota
+		inProgress
+		done
+	)
+
+	state := make(map[string]int)
+	var order []string
+	var path []string
+
+	var visit func(colour string) error
+	visit = func(colour string) error {
+		switch state[colour] {
+		case done:
+			return nil
+		case inProgress:
+			// Found a back edge, so `path` contains a cycle
+			// starting at `colour`.
+			for idx, pathColour := range path {
+				if pathColour == colour {
+					cycle := append(append([]string{}, path[idx:]...), colour)
+					return CycleError{Cycle: cycle}
+				}
+			}
+		}
+
+		state[colour] = inProgress
+		path = append(path, colour)
+
+		// Visiting in reverse alphabetical order keeps the
+		// reversed order roughly alphabetical.
+		children := sortedKeys(graph.contains[colour])
+		for i := len(children) - 1; i >= 0; i -= 1 {
+			if err := visit(children[i]); err != nil {
+				return err
+			}
+		}
+
+		path = path[:len(path)-1]
+		state[colour] = done
+		order = append(order, colour)
+
+		return nil
+	}
+
+	colours := graph.Colours()
+	for i := len(colours) - 1; i >= 0; i -= 1 {
+		if err := visit(colours[i]); err != nil {
+			return nil, err
+		}
+	}
+
+	for i, j := 0, len(order)-1; i < j; i, j = i+1, j-1 {
+		order[i], order[j] = order[j], order[i]
+	}
+
+	return order, nil
+}
+
+func copyCounts(counts map[string]int) map[string]int {
+	copied := make(map[string]int, len(counts))
+	for colour, count := range counts {
+		copied[colour] = count
+	}
+
+	return copied
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
@@ -0,0 +1,144 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Rules of the first example of the puzzle
+func exampleRules() map[string]map[string]int {
+	return map[string]map[string]int{
+		"light red":    {"bright white": 1, "muted yellow": 2},
+		"dark orange":  {"bright white": 3, "muted yellow": 4},
+		"bright white": {"shiny gold": 1},
+		"muted yellow": {"shiny gold": 2, "faded blue": 9},
+		"shiny gold":   {"dark olive": 1, "vibrant plum": 2},
+		"dark olive":   {"faded blue": 3, "dotted black": 4},
+		"vibrant plum": {"faded blue": 5, "dotted black": 6},
+		"faded blue":   {},
+		"dotted black": {},
+	}
+}
+
+func TestAncestors(t *testing.T) {
+	graph := newBagGraph(exampleRules())
+
+	assert.Equal(t, map[string]bool{
+		"bright white": true,
+		"muted yellow": true,
+		"dark orange":  true,
+		"light red":    true,
+	}, graph.Ancestors("shiny gold"))
+
+	assert.Empty(t, graph.Ancestors("light red"))
+}
+
+func TestContents(t *testing.T) {
+	graph := newBagGraph(exampleRules())
+
+	contents, err := graph.Contents("shiny gold")
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]int{
+		"dark olive":   1,
+		"vibrant plum": 2,
+		"faded blue":   13,
+		"dotted black": 16,
+	}, contents)
+
+	total, err := graph.TotalContents("shiny gold")
+	assert.NoError(t, err)
+	assert.Equal(t, 32, total)
+
+	total, err = graph.TotalContents("faded blue")
+	assert.NoError(t, err)
+	assert.Equal(t, 0, total)
+
+	_, err = graph.TotalContents("plaid purple")
+	assert.Error(t, err)
+}
+
+func TestContentsReturnsCopy(t *testing.T) {
+	graph := newBagGraph(exampleRules())
+
+	// Modifying results must not affect the memoized contents, nor the
+	// graph itself.
+	contents, err := graph.Contents("dark olive")
+	assert.NoError(t, err)
+	contents["faded blue"] = 1000
+
+	graph.Children("shiny gold")["dark olive"] = 1000
+	graph.Parents("faded blue")["muted yellow"] = 1000
+
+	total, err := graph.TotalContents("shiny gold")
+	assert.NoError(t, err)
+	assert.Equal(t, 32, total)
+
+	assert.Equal(t, 9, graph.Parents("faded blue")["muted yellow"])
+}
+
+func TestTotalContentsSecondExample(t *testing.T) {
+	graph := newBagGraph(map[string]map[string]int{
+		"shiny gold":  {"dark red": 2},
+		"dark red":    {"dark orange": 2},
+		"dark orange": {"dark yellow": 2},
+		"dark yellow": {"dark green": 2},
+		"dark green":  {"dark blue": 2},
+		"dark blue":   {"dark violet": 2},
+		"dark violet": {},
+	})
+
+	total, err := graph.TotalContents("shiny gold")
+	assert.NoError(t, err)
+	assert.Equal(t, 126, total)
+}
+
+func TestTopologicalOrder(t *testing.T) {
+	graph := newBagGraph(exampleRules())
+
+	order, err := graph.TopologicalOrder()
+	assert.NoError(t, err)
+	assert.ElementsMatch(t, graph.Colours(), order)
+
+	position := make(map[string]int)
+	for idx, colour := range order {
+		position[colour] = idx
+	}
+
+	for _, colour := range graph.Colours() {
+		for child := range graph.Children(colour) {
+			assert.Less(t, position[colour], position[child], "%s must come before %s", colour, child)
+		}
+	}
+
+	assert.Nil(t, graph.FindCycle())
+}
+
+func TestCycles(t *testing.T) {
+	rules := exampleRules()
+	// faded blue -> dark olive -> faded blue
+	rules["faded blue"] = map[string]int{"dark olive": 1}
+	graph := newBagGraph(rules)
+
+	cycle := graph.FindCycle()
+	assert.Len(t, cycle, 3)
+	assert.Equal(t, cycle[0], cycle[len(cycle)-1])
+	for i := 0; i < len(cycle)-1; i += 1 {
+		assert.Contains(t, graph.Children(cycle[i]), cycle[i+1])
+	}
+
+	_, err := graph.TopologicalOrder()
+	assert.IsType(t, CycleError{}, err)
+
+	_, err = graph.TotalContents("shiny gold")
+	assert.Equal(t, CycleError{Cycle: []string{"dark olive", "faded blue", "dark olive"}}, err)
+
+	// Colours which can't reach the cycle are unaffected
+	total, err := graph.TotalContents("dotted black")
+	assert.NoError(t, err)
+	assert.Equal(t, 0, total)
+
+	// Ancestors are well-defined despite the cycle
+	assert.True(t, graph.Ancestors("dark olive")["dark olive"])
+	assert.True(t, graph.Ancestors("dark olive")["light red"])
+}
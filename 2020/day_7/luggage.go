@@ -1,8 +1,10 @@
 package main
 
 import (
+	"flag"
 	"fmt"
 	"io/ioutil"
+	"os"
 	"regexp"
 	"strconv"
 	"strings"
@@ -10,9 +12,56 @@ import (
 
 const inputFile string = "luggage.input"
 
+var dotPath = flag.String("dot", "", "Write the containment graph in DOT format to this file")
+var highlightColour = flag.String("highlight", "shiny gold", "Colour whose part of the graph to highlight in the DOT output. Empty to disable")
+var highlightDirection = flag.String("direction", "contains", "Which part of the graph to highlight: 'contains' or 'contained-by'")
+
+var treeFormat = flag.String("tree", "", "Print the packing tree of a colour: 'text' or 'json'. Empty to disable")
+var treeColour = flag.String("tree-colour", "shiny gold", "Colour whose packing tree to print")
+var treeDepth = flag.Int("tree-depth", 0, "Maximum depth of the packing tree, 0 for no limit")
+var treeCollapse = flag.Bool("tree-collapse", false, "Expand subtrees of each colour only once in the packing tree")
+
 func main() {
+	flag.Parse()
+
 	taskOne()
 	taskTwo()
+
+	if *dotPath != "" {
+		exportDOT()
+	}
+
+	if *treeFormat != "" {
+		printPackingTree()
+	}
+}
+
+func printPackingTree() {
+	graph := newBagGraph(loadRules())
+
+	options := PackingOptions{MaxDepth: *treeDepth, CollapseIdentical: *treeCollapse}
+	tree, err := graph.PackingTree(*treeColour, options)
+	check(err)
+
+	switch *treeFormat {
+	case "text":
+		check(tree.WriteText(os.Stdout))
+	case "json":
+		check(tree.WriteJSON(os.Stdout))
+	default:
+		panic(fmt.Sprintf("Invalid packing tree format: %s\n", *treeFormat))
+	}
+}
+
+func exportDOT() {
+	direction, err := parseDirection(*highlightDirection)
+	check(err)
+
+	file, err := os.Create(*dotPath)
+	check(err)
+
+	check(writeDOT(file, newBagGraph(loadRules()), *highlightColour, direction))
+	check(file.Close())
 }
 
 func taskOne() {
@@ -20,29 +69,25 @@ func taskOne() {
 
 	// Rules specify, for each colour, which colours *it must contain*,
 	// whereas we care about *which colours gold can be contained by*.
-	mustContain := loadRules()
-
-	// Which we invert to get a map of what each colour may be contained
-	// in.
-	canBeContainedIn := invertRules(mustContain)
+	graph := newBagGraph(loadRules())
 
-	validColours := getOuterColoursFor("shiny gold", canBeContainedIn)
+	validColours := graph.Ancestors("shiny gold")
 	fmt.Printf("Valid colours to contain shiny gold: %d\n", len(validColours))
 }
 
 func taskTwo() {
 	fmt.Println("== Task two ==")
 
-	// Rules specify, for each colour, which colours *it must contain*,
-	// whereas we care about *which colours gold can be contained by*.
-	mustContain := loadRules()
+	graph := newBagGraph(loadRules())
+
+	contents, err := graph.Contents("shiny gold")
+	check(err)
 
-	contents := getBagContents("shiny gold", mustContain)
 	contentCount := 0
 	fmt.Printf("A shiny gold bag must contain:\n")
-	for bag, count := range contents {
-		fmt.Printf("  %d x %s\n", count, bag)
-		contentCount += count
+	for _, bag := range sortedKeys(contents) {
+		fmt.Printf("  %d x %s\n", contents[bag], bag)
+		contentCount += contents[bag]
 	}
 
 	fmt.Printf("Total content count: %d\n", contentCount)
@@ -54,79 +99,6 @@ func check(e error) {
 	}
 }
 
-// Return number and type of bags which given bag colour must contain.
-func getBagContents(colour string, mustContain map[string]map[string]int) map[string]int {
-	return getBagContentsRecurse(colour, mustContain, 0)
-}
-
-// This is the recursion body of `getBagContents`, not to be used directly.
-func getBagContentsRecurse(colour string, mustContain map[string]map[string]int, depth int) map[string]int {
-	contents := make(map[string]int)
-
-	rules, ok := mustContain[colour]
-	if !ok {
-		panic(fmt.Sprintf("No information which bags a bag of colour %s must contain.\n", colour))
-	}
-
-	fmt.Printf("%sColour %s must contain:\n", logPad(depth), colour)
-	for bag, count := range rules {
-		fmt.Printf("%s %d x %s\n", logPad(depth), count, bag)
-		contents[bag] += count
-
-		// We needn't guard against potential loops, as the task
-		// guarantees that there are none by asking for a finite number
-		// of bags which must be contained in a given bag.
-		innerContents := getBagContentsRecurse(bag, mustContain, depth+1)
-		for innerBag, innerCount := range innerContents {
-			contents[innerBag] += count * innerCount
-		}
-	}
-
-	return contents
-}
-
-// Return valid bag colours which the given bag colour may be contained in.
-func getOuterColoursFor(colour string, canBeContainedIn map[string]map[string]int) map[string]bool {
-	return getOuterColoursForRecurse(colour, canBeContainedIn, nil, 0)
-}
-
-// This is the recursion body of `getOuterColoursFor`, not to be used directly.
-func getOuterColoursForRecurse(colour string, canBeContainedIn map[string]map[string]int, validOuterColours map[string]bool, depth int) map[string]bool {
-	if validOuterColours == nil {
-		validOuterColours = make(map[string]bool)
-	}
-
-	outerColours, ok := canBeContainedIn[colour]
-	if !ok {
-		// Colour can seemingly not be contained in anything
-		return validOuterColours
-	}
-
-	fmt.Printf("%sColour %s may be contained in:\n", logPad(depth), colour)
-	for outerColour, count := range outerColours {
-		fmt.Printf("%s %s (x %d)\n", logPad(depth), outerColour, count)
-
-		// This is, to some extent, to optimize, but also to protect
-		// against potential loops in which bags must contain each
-		// other.
-		if _, ok := validOuterColours[outerColour]; !ok {
-			fmt.Printf("%s This is new information, adding to list\n", logPad(depth))
-			validOuterColours[outerColour] = true
-
-			// outerColour was not yet known to be able to contain this, recurse to find colours it can be contained by
-			newValidColours := getOuterColoursForRecurse(outerColour, canBeContainedIn, validOuterColours, depth+1)
-			for colour, _ := range newValidColours {
-				validOuterColours[colour] = true
-			}
-		} else {
-			// outerColour already known to be able to contain this, skip it
-			fmt.Printf("%s Already known, skipping\n", logPad(depth))
-		}
-	}
-
-	return validOuterColours
-}
-
 func invertRules(canContain map[string]map[string]int) map[string]map[string]int {
 	canBeContainedIn := make(map[string]map[string]int)
 
@@ -146,11 +118,6 @@ func invertRules(canContain map[string]map[string]int) map[string]map[string]int
 	return canBeContainedIn
 }
 
-func logPad(depth int) string {
-	formatString := "%" + strconv.Itoa(depth*2) + "s[%d] "
-	return fmt.Sprintf(formatString, "", depth)
-}
-
 // A map containing, for each colour, a map of colours it must contain.
 //
 // As an example, consider the following:
@@ -162,20 +129,34 @@ func logPad(depth int) string {
 // This means that blue bags must contain 2 red and 7 yellow bags. Gray bags must
 // not contain anything, and yellow bags must contain 1 black and 3 gray bags.
 func loadRules() map[string]map[string]int {
-	rules := make(map[string]map[string]int)
-
 	data, err := ioutil.ReadFile(inputFile)
-	// Remove trailing newline
-	data = data[:len(data)-1]
 	check(err)
 
-	// Matches the full line, capturing all 'can be contained in y' rules in one group
-	ruleMatcher := regexp.MustCompile("^([a-z ]+) bags contain (.*)\\.$")
+	rules, err := parseRules(string(data))
+	check(err)
+
+	return rules
+}
+
+// Matches the full line, capturing all 'can be contained in y' rules in one group
+var ruleMatcher = regexp.MustCompile(`^([a-z ]+) bags contain (.*)\.$`)
+
+// Matches individual 'can be contained in y' rule
+var containedInMatcher = regexp.MustCompile(`^(\d+) ([a-z ]+) bags?$`)
+
+// Regex-based equivalent of `parseRules`, which is stricter about whitespace
+// and doesn't report where parsing failed. Kept around as a baseline for
+// benchmarks.
+func parseRulesRegex(input string) (map[string]map[string]int, error) {
+	rules := make(map[string]map[string]int)
+
+	// Remove trailing newline
+	input = strings.TrimSuffix(input, "\n")
 
-	for _, line := range strings.Split(string(data), "\n") {
+	for _, line := range strings.Split(input, "\n") {
 		match := ruleMatcher.FindStringSubmatch(line)
 		if match == nil {
-			panic(fmt.Sprintf("Line did not match pattern: %s\n", line))
+			return nil, fmt.Errorf("Line did not match pattern: %s", line)
 		}
 
 		parentColour := match[1]
@@ -184,32 +165,35 @@ func loadRules() map[string]map[string]int {
 		if match[2] == "no other bags" {
 			rules[parentColour] = make(map[string]int)
 		} else {
-			rules[parentColour] = extractChildRules(childRules)
+			children, err := extractChildRules(childRules)
+			if err != nil {
+				return nil, err
+			}
+			rules[parentColour] = children
 		}
 	}
 
-	return rules
+	return rules, nil
 }
 
-func extractChildRules(line string) map[string]int {
+func extractChildRules(line string) (map[string]int, error) {
 	rules := make(map[string]int)
 
 	// Individual rules are split by ", "
 	for _, childRule := range strings.Split(line, ", ") {
-		// Matches individual 'can be contained in y' rule
-		containedInMatcher := regexp.MustCompile("^(\\d+) ([a-z ]+) bags?$")
-
 		innerMatch := containedInMatcher.FindStringSubmatch(childRule)
 		if innerMatch == nil {
-			panic(fmt.Sprintf("Rule did not match pattern: '%s'\n", childRule))
+			return nil, fmt.Errorf("Rule did not match pattern: '%s'", childRule)
 		}
 
 		childColour := innerMatch[2]
 		childCount, err := strconv.Atoi(innerMatch[1])
-		check(err)
+		if err != nil {
+			return nil, err
+		}
 
 		rules[childColour] = childCount
 	}
 
-	return rules
+	return rules, nil
 }
@@ -0,0 +1,137 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// A bag within a packing tree, along with the bags it must contain.
+type PackingNode struct {
+	Colour string `json:"colour"`
+
+	// Number of bags of this colour within its parent bag.
+	Count int `json:"count"`
+
+	// Number of bags of this colour within the root bag, via this node's
+	// path. This is the product of the counts along that path.
+	Multiplicity int `json:"multiplicity"`
+
+	// Number of bags which a single bag of this colour must contain,
+	// including bags inside of bags.
+	Subtotal int `json:"subtotal"`
+
+	Children []*PackingNode `json:"children,omitempty"`
+
+	// Set if this node's children were omitted due to the depth limit.
+	Truncated bool `json:"truncated,omitempty"`
+
+	// Set if this node's children were omitted as a subtree of the same
+	// colour was expanded earlier in the tree.
+	Collapsed bool `json:"collapsed,omitempty"`
+}
+
+type PackingOptions struct {
+	// Maximum depth of the tree, with the root being at depth 0. Zero or
+	// less means no limit.
+	MaxDepth int
+
+	// Expand the subtree of each colour only once, as the tree can get
+	// rather large otherwise.
+	CollapseIdentical bool
+}
+
+// Build the full tree of bags which a bag of the given colour must contain,
+// rather than the aggregated counts returned by `Contents`.
+//
+// Returns an error if the colour is unknown, or if its contents are infinite
+// due to cyclic rules.
+func (graph *BagGraph) PackingTree(colour string, options PackingOptions) (*PackingNode, error) {
+	// This makes sure the colour is known and there are no cycles below
+	// it, so the recursion below terminates.
+	if _, err := graph.Contents(colour); err != nil {
+		return nil, err
+	}
+
+	root := PackingNode{Colour: colour, Count: 1, Multiplicity: 1}
+	graph.packingTreeRecurse(&root, 0, options, make(map[string]bool))
+
+	return &root, nil
+}
+
+// This is the recursion body of `PackingTree`, not to be used directly.
+// `expanded` holds the colours whose subtrees were expanded already.
+func (graph *BagGraph) packingTreeRecurse(node *PackingNode, depth int, options PackingOptions, expanded map[string]bool) {
+	// Contents are known to be well-defined at this point
+	subtotal, _ := graph.TotalContents(node.Colour)
+	node.Subtotal = subtotal
+
+	children := graph.Children(node.Colour)
+	if len(children) == 0 {
+		return
+	}
+
+	if options.MaxDepth > 0 && depth >= options.MaxDepth {
+		node.Truncated = true
+		return
+	}
+
+	if options.CollapseIdentical && expanded[node.Colour] {
+		node.Collapsed = true
+		return
+	}
+	expanded[node.Colour] = true
+
+	for _, colour := range sortedKeys(children) {
+		child := PackingNode{
+			Colour:       colour,
+			Count:        children[colour],
+			Multiplicity: node.Multiplicity * children[colour],
+		}
+		graph.packingTreeRecurse(&child, depth+1, options, expanded)
+
+		node.Children = append(node.Children, &child)
+	}
+}
+
+// Render the tree as indented text, with one line per node.
+func (node *PackingNode) WriteText(w io.Writer) error {
+	var out strings.Builder
+	node.writeTextRecurse(&out, 0)
+
+	_, err := io.WriteString(w, out.String())
+	return err
+}
+
+func (node *PackingNode) writeTextRecurse(out *strings.Builder, depth int) {
+	if depth == 0 {
+		fmt.Fprintf(out, "%s: %d bags inside", node.Colour, node.Subtotal)
+	} else {
+		fmt.Fprintf(
+			out,
+			"%s%d x %s (%d in total): %d bags inside each",
+			strings.Repeat("  ", depth), node.Count, node.Colour, node.Multiplicity, node.Subtotal,
+		)
+	}
+
+	switch {
+	case node.Truncated:
+		fmt.Fprintf(out, ", depth limit reached")
+	case node.Collapsed:
+		fmt.Fprintf(out, ", expanded above")
+	}
+	fmt.Fprintf(out, "\n")
+
+	for _, child := range node.Children {
+		child.writeTextRecurse(out, depth+1)
+	}
+}
+
+// Render the tree as indented JSON.
+func (node *PackingNode) WriteJSON(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+
+	return encoder.Encode(node)
+}
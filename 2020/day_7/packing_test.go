@@ -0,0 +1,104 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPackingTree(t *testing.T) {
+	graph := newBagGraph(exampleRules())
+
+	tree, err := graph.PackingTree("shiny gold", PackingOptions{})
+	assert.NoError(t, err)
+
+	assert.Equal(t, 32, tree.Subtotal)
+	assert.Len(t, tree.Children, 2)
+
+	plum := tree.Children[1]
+	assert.Equal(t, "vibrant plum", plum.Colour)
+	assert.Equal(t, 2, plum.Count)
+	assert.Equal(t, 2, plum.Multiplicity)
+	assert.Equal(t, 11, plum.Subtotal)
+
+	black := plum.Children[0]
+	assert.Equal(t, "dotted black", black.Colour)
+	assert.Equal(t, 6, black.Count)
+	assert.Equal(t, 12, black.Multiplicity)
+	assert.Equal(t, 0, black.Subtotal)
+	assert.Empty(t, black.Children)
+
+	// Summing up multiplicities of all nodes yields the total
+	var sum func(node *PackingNode) int
+	sum = func(node *PackingNode) int {
+		total := 0
+		for _, child := range node.Children {
+			total += child.Multiplicity + sum(child)
+		}
+		return total
+	}
+	assert.Equal(t, tree.Subtotal, sum(tree))
+}
+
+func TestPackingTreeOptions(t *testing.T) {
+	graph := newBagGraph(exampleRules())
+
+	{
+		tree, err := graph.PackingTree("light red", PackingOptions{MaxDepth: 2})
+		assert.NoError(t, err)
+
+		white := tree.Children[0]
+		assert.Equal(t, "bright white", white.Colour)
+		gold := white.Children[0]
+		assert.True(t, gold.Truncated)
+		assert.Empty(t, gold.Children)
+		// Subtotals are unaffected by truncation
+		assert.Equal(t, 32, gold.Subtotal)
+	}
+
+	{
+		tree, err := graph.PackingTree("light red", PackingOptions{CollapseIdentical: true})
+		assert.NoError(t, err)
+
+		// Shiny gold is expanded within bright white, but not within
+		// muted yellow
+		white, yellow := tree.Children[0], tree.Children[1]
+		assert.False(t, white.Children[0].Collapsed)
+		assert.Len(t, white.Children[0].Children, 2)
+
+		assert.Equal(t, "shiny gold", yellow.Children[1].Colour)
+		assert.True(t, yellow.Children[1].Collapsed)
+		assert.Empty(t, yellow.Children[1].Children)
+	}
+
+	{
+		_, err := graph.PackingTree("plaid purple", PackingOptions{})
+		assert.Error(t, err)
+	}
+}
+
+func TestPackingTreeRendering(t *testing.T) {
+	graph := newBagGraph(exampleRules())
+	tree, err := graph.PackingTree("shiny gold", PackingOptions{MaxDepth: 1})
+	assert.NoError(t, err)
+
+	{
+		var buf bytes.Buffer
+		assert.NoError(t, tree.WriteText(&buf))
+		assert.Equal(t, "shiny gold: 32 bags inside\n"+
+			"  1 x dark olive (1 in total): 7 bags inside each, depth limit reached\n"+
+			"  2 x vibrant plum (2 in total): 11 bags inside each, depth limit reached\n",
+			buf.String())
+	}
+
+	{
+		var buf bytes.Buffer
+		assert.NoError(t, tree.WriteJSON(&buf))
+
+		var decoded PackingNode
+		assert.NoError(t, json.Unmarshal(buf.Bytes(), &decoded))
+		assert.Equal(t, *tree, decoded)
+	}
+}
@@ -0,0 +1,273 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Parser for bag rules, which follow this grammar:
+//
+//   rule     := colour bags "contain" contents "."
+//   contents := "no" "other" bags | item { "," item }
+//   item     := number colour bags
+//   colour   := word { word }
+//   bags     := "bag" | "bags"
+//
+// Words and numbers are separated by whitespace, which is otherwise
+// insignificant. Empty lines are skipped.
+
+type tokenKind int
+
+const (
+	wordToken tokenKind = iota
+	numberToken
+	commaToken
+	periodToken
+	endToken
+)
+
+func (kind tokenKind) String() string {
+	switch kind {
+	case wordToken:
+		return "word"
+	case numberToken:
+		return "number"
+	case commaToken:
+		return "','"
+	case periodToken:
+		return "'.'"
+	default:
+		return "end of line"
+	}
+}
+
+type token struct {
+	kind  tokenKind
+	value string
+	// One-based column of the token's first character
+	column int
+}
+
+// Error in the input of the bag-rule parser, with one-based line and column
+// of where it was encountered.
+type ParseError struct {
+	Line   int
+	Column int
+	Msg    string
+}
+
+func (err ParseError) Error() string {
+	return fmt.Sprintf("Line %d, column %d: %s", err.Line, err.Column, err.Msg)
+}
+
+// Split a line into tokens, terminated by an end token.
+func tokenize(line string, lineNumber int) ([]token, error) {
+	var tokens []token
+
+	for pos := 0; pos < len(line); {
+		char := line[pos]
+
+		switch {
+		case char == ' ' || char == '\t' || char == '\r':
+			pos += 1
+		case char == ',':
+			tokens = append(tokens, token{kind: commaToken, value: ",", column: pos + 1})
+			pos += 1
+		case char == '.':
+			tokens = append(tokens, token{kind: periodToken, value: ".", column: pos + 1})
+			pos += 1
+		case char >= 'a' && char <= 'z':
+			start := pos
+			for pos < len(line) && line[pos] >= 'a' && line[pos] <= 'z' {
+				pos += 1
+			}
+			tokens = append(tokens, token{kind: wordToken, value: line[start:pos], column: start + 1})
+		case char >= '0' && char <= '9':
+			start := pos
+			for pos < len(line) && line[pos] >= '0' && line[pos] <= '9' {
+				pos += 1
+			}
+			tokens = append(tokens, token{kind: numberToken, value: line[start:pos], column: start + 1})
+		default:
+			return nil, ParseError{Line: lineNumber, Column: pos + 1, Msg: fmt.Sprintf("Unexpected character '%c'", char)}
+		}
+	}
+
+	tokens = append(tokens, token{kind: endToken, column: len(line) + 1})
+	return tokens, nil
+}
+
+// Recursive-descent parser over the tokens of a single line.
+type ruleParser struct {
+	tokens []token
+	pos    int
+	line   int
+}
+
+func (parser *ruleParser) peek() token {
+	return parser.tokens[parser.pos]
+}
+
+func (parser *ruleParser) next() token {
+	tok := parser.tokens[parser.pos]
+	if tok.kind != endToken {
+		parser.pos += 1
+	}
+
+	return tok
+}
+
+func (parser *ruleParser) errorf(tok token, format string, args ...interface{}) error {
+	return ParseError{Line: parser.line, Column: tok.column, Msg: fmt.Sprintf(format, args...)}
+}
+
+// Consume a token of the given kind, and - if non-empty - value.
+func (parser *ruleParser) expect(kind tokenKind, value string) (token, error) {
+	tok := parser.next()
+
+	if tok.kind != kind || value != "" && tok.value != value {
+		expected := kind.String()
+		if value != "" {
+			expected = "'" + value + "'"
+		}
+
+		return tok, parser.errorf(tok, "Expected %s, got %s", expected, describeToken(tok))
+	}
+
+	return tok, nil
+}
+
+func describeToken(tok token) string {
+	if tok.kind == wordToken || tok.kind == numberToken {
+		return "'" + tok.value + "'"
+	}
+
+	return tok.kind.String()
+}
+
+func isBags(tok token) bool {
+	return tok.kind == wordToken && (tok.value == "bag" || tok.value == "bags")
+}
+
+// Parse a colour, followed by "bag" or "bags".
+func (parser *ruleParser) colourAndBags() (string, error) {
+	var words []string
+
+	for {
+		tok := parser.peek()
+		if isBags(tok) {
+			if len(words) == 0 {
+				return "", parser.errorf(tok, "Expected colour, got %s", describeToken(tok))
+			}
+
+			parser.next()
+			return strings.Join(words, " "), nil
+		}
+
+		if tok.kind != wordToken {
+			if len(words) == 0 {
+				return "", parser.errorf(tok, "Expected colour, got %s", describeToken(tok))
+			}
+
+			return "", parser.errorf(tok, "Expected 'bag' or 'bags', got %s", describeToken(tok))
+		}
+
+		words = append(words, parser.next().value)
+	}
+}
+
+func (parser *ruleParser) rule() (string, map[string]int, error) {
+	colour, err := parser.colourAndBags()
+	if err != nil {
+		return "", nil, err
+	}
+
+	if _, err := parser.expect(wordToken, "contain"); err != nil {
+		return "", nil, err
+	}
+
+	contents := make(map[string]int)
+
+	if parser.peek().kind == wordToken && parser.peek().value == "no" {
+		parser.next()
+		if _, err := parser.expect(wordToken, "other"); err != nil {
+			return "", nil, err
+		}
+
+		if tok := parser.next(); !isBags(tok) {
+			return "", nil, parser.errorf(tok, "Expected 'bag' or 'bags', got %s", describeToken(tok))
+		}
+	} else {
+		for {
+			countToken, err := parser.expect(numberToken, "")
+			if err != nil {
+				return "", nil, err
+			}
+
+			count, err := strconv.Atoi(countToken.value)
+			if err != nil {
+				return "", nil, parser.errorf(countToken, "Invalid count: %v", err)
+			}
+
+			child, err := parser.colourAndBags()
+			if err != nil {
+				return "", nil, err
+			}
+
+			if _, ok := contents[child]; ok {
+				return "", nil, parser.errorf(countToken, "Duplicate contents: %s", child)
+			}
+			contents[child] = count
+
+			if parser.peek().kind != commaToken {
+				break
+			}
+			parser.next()
+		}
+	}
+
+	if _, err := parser.expect(periodToken, ""); err != nil {
+		return "", nil, err
+	}
+
+	if _, err := parser.expect(endToken, ""); err != nil {
+		return "", nil, err
+	}
+
+	return colour, contents, nil
+}
+
+// Parse bag rules into the format described at `loadRules`.
+//
+// Returns a `ParseError` on invalid input, or if there are multiple rules for
+// the same colour.
+func parseRules(input string) (map[string]map[string]int, error) {
+	rules := make(map[string]map[string]int)
+
+	for idx, line := range strings.Split(input, "\n") {
+		lineNumber := idx + 1
+
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+
+		tokens, err := tokenize(line, lineNumber)
+		if err != nil {
+			return nil, err
+		}
+
+		parser := ruleParser{tokens: tokens, line: lineNumber}
+		colour, contents, err := parser.rule()
+		if err != nil {
+			return nil, err
+		}
+
+		if _, ok := rules[colour]; ok {
+			return nil, ParseError{Line: lineNumber, Column: tokens[0].column, Msg: fmt.Sprintf("Duplicate rule for colour %s", colour)}
+		}
+		rules[colour] = contents
+	}
+
+	return rules, nil
+}
@@ -0,0 +1,111 @@
+package main
+
+import (
+	"io/ioutil"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const exampleInput = `light red bags contain 1 bright white bag, 2 muted yellow bags.
+dark orange bags contain 3 bright white bags, 4 muted yellow bags.
+bright white bags contain 1 shiny gold bag.
+muted yellow bags contain 2 shiny gold bags, 9 faded blue bags.
+shiny gold bags contain 1 dark olive bag, 2 vibrant plum bags.
+dark olive bags contain 3 faded blue bags, 4 dotted black bags.
+vibrant plum bags contain 5 faded blue bags, 6 dotted black bags.
+faded blue bags contain no other bags.
+dotted black bags contain no other bags.
+`
+
+func TestParseRules(t *testing.T) {
+	rules, err := parseRules(exampleInput)
+	assert.NoError(t, err)
+	assert.Equal(t, exampleRules(), rules)
+}
+
+func TestParseRulesLenient(t *testing.T) {
+	input := "shiny gold bag contain 1 dark olive bags ,2 vibrant  plum bag.  \r\n" +
+		"\n" +
+		"\tdark olive bags contain no other bag.\n" +
+		"vibrant plum bags contain no other bags .\n"
+
+	rules, err := parseRules(input)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]map[string]int{
+		"shiny gold":   {"dark olive": 1, "vibrant plum": 2},
+		"dark olive":   {},
+		"vibrant plum": {},
+	}, rules)
+}
+
+func TestParseRulesErrors(t *testing.T) {
+	examples := []struct {
+		input  string
+		line   int
+		column int
+	}{
+		// Missing period
+		{"faded blue bags contain no other bags\n", 1, 38},
+		// Missing count
+		{"faded blue bags contain no other bags.\nshiny gold bags contain dark olive bags.\n", 2, 25},
+		// Missing colour
+		{"shiny gold bags contain 1 bags.", 1, 27},
+		{"bags contain no other bags.", 1, 1},
+		// Invalid character
+		{"shiny gold bags contain 1 dark-olive bag.", 1, 31},
+		// Missing 'bags' after colour
+		{"shiny gold bags contain 1 dark olive, 2 vibrant plum bags.", 1, 37},
+		// Trailing garbage
+		{"shiny gold bags contain no other bags. 5", 1, 40},
+		// Duplicate rule
+		{"shiny gold bags contain no other bags.\nshiny gold bags contain no other bags.", 2, 1},
+		// Duplicate contents
+		{"shiny gold bags contain 1 dark olive bag, 2 dark olive bags.", 1, 43},
+	}
+
+	for _, example := range examples {
+		_, err := parseRules(example.input)
+		assert.Error(t, err, example.input)
+
+		parseErr, ok := err.(ParseError)
+		assert.True(t, ok, example.input)
+		assert.Equal(t, example.line, parseErr.Line, example.input)
+		assert.Equal(t, example.column, parseErr.Column, example.input)
+	}
+}
+
+func TestParseRulesMatchesRegex(t *testing.T) {
+	data, err := ioutil.ReadFile(inputFile)
+	assert.NoError(t, err)
+
+	rules, err := parseRules(string(data))
+	assert.NoError(t, err)
+
+	regexRules, err := parseRulesRegex(string(data))
+	assert.NoError(t, err)
+
+	assert.Equal(t, regexRules, rules)
+}
+
+func BenchmarkParseRules(b *testing.B) {
+	data, err := ioutil.ReadFile(inputFile)
+	assert.NoError(b, err)
+	input := string(data)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i += 1 {
+		_, _ = parseRules(input)
+	}
+}
+
+func BenchmarkParseRulesRegex(b *testing.B) {
+	data, err := ioutil.ReadFile(inputFile)
+	assert.NoError(b, err)
+	input := string(data)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i += 1 {
+		_, _ = parseRulesRegex(input)
+	}
+}
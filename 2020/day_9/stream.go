@@ -0,0 +1,136 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// A number which is not the sum of two of the numbers preceding it.
+type InvalidNumber struct {
+	// Zero-based index of the number within the stream
+	Index int
+	Value int
+	// The preceding numbers at that time, oldest first
+	Window []int
+}
+
+// Validates a stream of numbers one at a time, keeping only the window of
+// preceding numbers in memory.
+//
+// Rather than searching the window from scratch for each number, as
+// `findFirstInvalid` does, it maintains the sums of all pairs within the
+// window incrementally. Each number then takes O(preamble) to add to the
+// window, and O(1) to validate.
+type StreamValidator struct {
+	preamble int
+
+	// Ring buffer of the window, with `next` pointing to its oldest entry
+	// once full.
+	window []int
+	next   int
+
+	// Number of pairs of distinct entries within the window summing to
+	// each value.
+	sums map[int]int
+
+	// Number of numbers processed so far
+	count int
+}
+
+func newStreamValidator(preamble int) (*StreamValidator, error) {
+	if preamble < 2 {
+		return nil, fmt.Errorf("Invalid preamble length %d", preamble)
+	}
+
+	return &StreamValidator{preamble: preamble, sums: make(map[int]int)}, nil
+}
+
+// Process the next number of the stream.
+//
+// Returns the invalid number and true if it is not the sum of two numbers
+// within the window. Numbers which are part of the preamble are always valid.
+func (validator *StreamValidator) Push(x int) (InvalidNumber, bool) {
+	var invalid InvalidNumber
+	isInvalid := false
+
+	if len(validator.window) == validator.preamble && validator.sums[x] == 0 {
+		invalid = InvalidNumber{Index: validator.count, Value: x, Window: validator.Window()}
+		isInvalid = true
+	}
+
+	if len(validator.window) == validator.preamble {
+		// Evict the oldest number, along with all its sums
+		oldest := validator.window[validator.next]
+		for idx, y := range validator.window {
+			if idx != validator.next {
+				validator.removeSum(oldest + y)
+			}
+		}
+
+		for idx, y := range validator.window {
+			if idx != validator.next {
+				validator.sums[x+y] += 1
+			}
+		}
+		validator.window[validator.next] = x
+		validator.next = (validator.next + 1) % validator.preamble
+	} else {
+		for _, y := range validator.window {
+			validator.sums[x+y] += 1
+		}
+		validator.window = append(validator.window, x)
+	}
+
+	validator.count += 1
+	return invalid, isInvalid
+}
+
+// Current window, oldest number first.
+func (validator *StreamValidator) Window() []int {
+	window := make([]int, 0, len(validator.window))
+	window = append(window, validator.window[validator.next:]...)
+	window = append(window, validator.window[:validator.next]...)
+
+	return window
+}
+
+func (validator *StreamValidator) removeSum(sum int) {
+	validator.sums[sum] -= 1
+	if validator.sums[sum] == 0 {
+		delete(validator.sums, sum)
+	}
+}
+
+// Validate numbers read from `reader`, one per line, calling `emit` for each
+// invalid number as soon as it is read. Empty lines are skipped.
+//
+// Returns an error if the input can't be read or contains anything other than
+// numbers.
+func validateStream(reader io.Reader, preamble int, emit func(InvalidNumber)) error {
+	validator, err := newStreamValidator(preamble)
+	if err != nil {
+		return err
+	}
+
+	scanner := bufio.NewScanner(reader)
+	for lineNumber := 1; scanner.Scan(); lineNumber += 1 {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+
+		x, err := strconv.Atoi(line)
+		if err != nil {
+			return fmt.Errorf("Line %d: %v", lineNumber, err)
+		}
+
+		if invalid, ok := validator.Push(x); ok {
+			emit(invalid)
+		}
+	}
+
+	return scanner.Err()
+}
@@ -0,0 +1,78 @@
+package main
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/lavode/adventofcode/2020/pkg/ksum"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateStream(t *testing.T) {
+	file, err := os.Open("xmas_crypto.input.test1")
+	assert.NoError(t, err)
+	defer file.Close()
+
+	var invalid []InvalidNumber
+	err = validateStream(file, 5, func(x InvalidNumber) {
+		invalid = append(invalid, x)
+	})
+	assert.NoError(t, err)
+	assert.Equal(
+		t,
+		[]InvalidNumber{{Index: 14, Value: 127, Window: []int{95, 102, 117, 150, 182}}},
+		invalid,
+	)
+}
+
+func TestValidateStreamMatchesBatch(t *testing.T) {
+	numbers := loadNumbers(inputFile)
+
+	var expected []int
+	for i := defaultPreamble; i < len(numbers); i += 1 {
+		if _, err := ksum.FindKWithSum(numbers[i-defaultPreamble:i], 2, numbers[i]); err != nil {
+			expected = append(expected, i)
+		}
+	}
+
+	validator, err := newStreamValidator(defaultPreamble)
+	assert.NoError(t, err)
+
+	var actual []int
+	for idx, x := range numbers {
+		if invalid, ok := validator.Push(x); ok {
+			assert.Equal(t, idx, invalid.Index)
+			assert.Equal(t, numbers[idx-defaultPreamble:idx], invalid.Window)
+			actual = append(actual, idx)
+		}
+	}
+
+	assert.Equal(t, expected, actual)
+}
+
+func TestStreamValidatorDuplicates(t *testing.T) {
+	validator, err := newStreamValidator(2)
+	assert.NoError(t, err)
+
+	// Equal numbers at distinct positions form a valid pair
+	for _, x := range []int{5, 5, 10} {
+		_, ok := validator.Push(x)
+		assert.False(t, ok)
+	}
+
+	// Window is now [5, 10], the sum of the evicted pair must be gone
+	_, ok := validator.Push(10)
+	assert.True(t, ok)
+	_, ok = validator.Push(15)
+	assert.True(t, ok)
+	assert.Equal(t, []int{10, 15}, validator.Window())
+}
+
+func TestValidateStreamErrors(t *testing.T) {
+	err := validateStream(strings.NewReader("1\n2\nthree\n"), 2, func(InvalidNumber) {})
+	assert.EqualError(t, err, `Line 3: strconv.Atoi: parsing "three": invalid syntax`)
+
+	err = validateStream(strings.NewReader("1\n2\n"), 1, func(InvalidNumber) {})
+	assert.Error(t, err)
+}
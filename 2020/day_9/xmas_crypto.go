@@ -1,14 +1,21 @@
 package main
 
 import (
+	"flag"
 	"fmt"
 	"io/ioutil"
+	"os"
 	"strconv"
 	"strings"
+
+	"github.com/lavode/adventofcode/2020/pkg/ksum"
 )
 
 const inputFile string = "xmas_crypto.input"
 
+// Default number of preceding numbers each number must be a sum of.
+const defaultPreamble int = 25
+
 // 'Manual' queue, which has to be told when to shift its window.
 type Queue struct {
 	slice []int
@@ -36,7 +43,24 @@ func (queue *Queue) Shift() error {
 	}
 }
 
+var preamble = flag.Int("preamble", defaultPreamble, "Number of preceding numbers each number must be a sum of")
+var stream = flag.Bool("stream", false, "Validate numbers read from stdin instead, reporting every invalid number")
+
 func main() {
+	flag.Parse()
+
+	if *stream {
+		err := validateStream(os.Stdin, *preamble, func(invalid InvalidNumber) {
+			fmt.Printf(
+				"Invalid number: %d at index %d, not a sum of %+v\n",
+				invalid.Value, invalid.Index, invalid.Window,
+			)
+		})
+		check(err)
+
+		return
+	}
+
 	taskOne()
 	taskTwo()
 }
@@ -44,16 +68,44 @@ func main() {
 func taskOne() {
 	fmt.Println("== Task one ==")
 
-	numbers := loadNumbers()
-	queue := Queue{slice: numbers, end: 25}
+	numbers := loadNumbers(inputFile)
+
+	idx, err := findFirstInvalid(numbers, *preamble)
+	check(err)
+	fmt.Printf(
+		"First invalid number: %d at index %d, not a sum of %+v\n",
+		numbers[idx], idx, numbers[idx-*preamble:idx],
+	)
+}
+
+func taskTwo() {
+	fmt.Println("== Task two ==")
+
+	numbers := loadNumbers(inputFile)
+
+	start, end, weakness, err := encryptionWeakness(numbers, *preamble)
+	check(err)
+	fmt.Printf("Numbers: %+v\n", numbers[start:end])
+	fmt.Printf("Encryption weakness: %d\n", weakness)
+}
+
+// Find the index of the first number which is not the sum of two of the
+// `preamble` numbers preceding it.
+//
+// Returns an error if there is no such number.
+func findFirstInvalid(numbers []int, preamble int) (int, error) {
+	if preamble < 2 || preamble > len(numbers) {
+		return 0, fmt.Errorf("Invalid preamble length %d for %d numbers", preamble, len(numbers))
+	}
 
-	// We'll start with the 26th items, with the 'queue' containing the 25
-	// preceding it.
-	for i := 25; i < len(numbers); i += 1 {
-		sum := numbers[i]
-		_, _, err := findPairWithSum(queue.Items(), sum)
+	queue := Queue{slice: numbers, end: preamble}
+
+	// We'll start with the first item after the preamble, with the
+	// 'queue' containing the items preceding it.
+	for i := preamble; i < len(numbers); i += 1 {
+		_, err := ksum.FindKWithSum(queue.Items(), 2, numbers[i])
 		if err != nil {
-			fmt.Printf("Error: %d can not be expressed as sum of %+v!\n", sum, queue)
+			return i, nil
 		}
 
 		e := queue.Shift()
@@ -63,59 +115,64 @@ func taskOne() {
 			panic(fmt.Sprintf("Ran out of items while shifting queue\n"))
 		}
 	}
-}
 
-func taskTwo() {
-	fmt.Println("== Task two ==")
+	return 0, fmt.Errorf("All numbers are valid")
+}
 
-	numbers := loadNumbers()
-	// We now need to find a continguous sequence of numbers in the input
-	// which sums to the number found above.
-	// We'll do so by starting with the pair of the first two items, adding
-	// the following items until we exceed the sum.  If we exceed the sum,
-	// we start with the pair of items 2 and 3, and so on.
-	// This is an O(n^2) solution, but for this case good enough.
-	goal := 90433990
-	for start := 0; start <= len(numbers)-2; start += 1 {
-		for end := start + 2; end <= len(numbers); end += 1 {
-			sum := 0
-			for _, x := range numbers[start:end] {
-				sum += x
+// Find a contiguous range numbers[start:end] of at least two numbers which
+// sums to `goal`.
+//
+// Returns an error if there is no such range.
+// O(n) implementation, utilizing prefix sums: numbers[start:end] sums to
+// `goal` iff prefix(end) - prefix(start) = goal, where prefix(i) is the sum of
+// the first i numbers. Unlike a sliding window, this works for negative
+// numbers too.
+func findRangeWithSum(numbers []int, goal int) (int, int, error) {
+	// Index of the first occurrence of each prefix sum
+	prefixes := make(map[int]int)
+	prefix := make([]int, len(numbers)+1)
+
+	for end := 1; end <= len(numbers); end += 1 {
+		prefix[end] = prefix[end-1] + numbers[end-1]
+
+		// The range must contain at least two numbers, so only prefixes
+		// up to end - 2 are eligible starts.
+		if end >= 2 {
+			if _, ok := prefixes[prefix[end-2]]; !ok {
+				prefixes[prefix[end-2]] = end - 2
 			}
+		}
 
-			if sum == goal {
-				// We got the goal :)
-				fmt.Printf("Success: Indices %d through %d sum to %d\n", start, end, goal)
-				fmt.Printf("Numbers: %+v\n", numbers[start:end])
-			}
+		if start, ok := prefixes[prefix[end]-goal]; ok {
+			return start, end, nil
 		}
 	}
+
+	return 0, 0, fmt.Errorf("No contiguous range sums to %d", goal)
 }
 
-// Find a pair of numbers x and y in a slice of numbers with x + y = sum.
+// Find the encryption weakness, ie the sum of the smallest and largest number
+// in the contiguous range which sums to the first invalid number.
 //
-// Returns an error if no pair found.
-// O(n) implementation, utilizing a hashmap as auxiliary data structure.
-func findPairWithSum(numbers []int, sum int) (int, int, error) {
-	// We don't really care about the value type of the map, but GoLang has
-	// no native support for sets
-	cache := make(map[int]bool)
-
-	for _, a := range numbers {
-		b := sum - a
-
-		if _, ok := cache[b]; ok {
-			// A value `b` has been seen in `numbers` earlier.
-			return a, b, nil
-		} else {
-			// No value `b` was seen in `numbers` so far, add
-			// current value `a` to cache
-			cache[a] = true
-		}
+// Returns the range as numbers[start:end], and the weakness.
+func encryptionWeakness(numbers []int, preamble int) (int, int, int, error) {
+	idx, err := findFirstInvalid(numbers, preamble)
+	if err != nil {
+		return 0, 0, 0, err
+	}
+
+	start, end, err := findRangeWithSum(numbers, numbers[idx])
+	if err != nil {
+		return 0, 0, 0, err
+	}
+
+	smallest, largest := numbers[start], numbers[start]
+	for _, x := range numbers[start:end] {
+		smallest = min(smallest, x)
+		largest = max(largest, x)
 	}
 
-	// No matching pair found
-	return 0, 0, fmt.Errorf("No pair (x, y) with x + y = %d found", sum)
+	return start, end, smallest + largest, nil
 }
 
 func check(e error) {
@@ -124,16 +181,14 @@ func check(e error) {
 	}
 }
 
-func loadNumbers() []int {
-	data, err := ioutil.ReadFile(inputFile)
-	// Remove trailing newline
-	data = data[:len(data)-1]
+func loadNumbers(path string) []int {
+	data, err := ioutil.ReadFile(path)
 	check(err)
 
 	var numbers []int
 
 	// ioutil.ReadFile returns a byte slice, strings.Split expects a string
-	for _, s := range strings.Split(string(data), "\n") {
+	for _, s := range strings.Split(strings.TrimSuffix(string(data), "\n"), "\n") {
 		i, err := strconv.Atoi(s)
 		check(err)
 
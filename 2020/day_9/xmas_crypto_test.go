@@ -0,0 +1,67 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFindFirstInvalid(t *testing.T) {
+	numbers := loadNumbers("xmas_crypto.input.test1")
+
+	idx, err := findFirstInvalid(numbers, 5)
+	assert.NoError(t, err)
+	assert.Equal(t, 14, idx)
+	assert.Equal(t, 127, numbers[idx])
+
+	_, err = findFirstInvalid(numbers[:14], 5)
+	assert.Error(t, err)
+
+	_, err = findFirstInvalid(numbers, 1)
+	assert.Error(t, err)
+}
+
+func TestFindRangeWithSum(t *testing.T) {
+	numbers := loadNumbers("xmas_crypto.input.test1")
+
+	start, end, err := findRangeWithSum(numbers, 127)
+	assert.NoError(t, err)
+	assert.Equal(t, []int{15, 25, 47, 40}, numbers[start:end])
+
+	{
+		// Single numbers don't count as a range
+		_, _, err := findRangeWithSum([]int{1, 5, 7}, 5)
+		assert.Error(t, err)
+	}
+
+	{
+		// Negative numbers are fine too
+		start, end, err := findRangeWithSum([]int{4, -3, 9, -1, 2}, 7)
+		assert.NoError(t, err)
+		assert.Equal(t, 1, start)
+		assert.Equal(t, 5, end)
+	}
+}
+
+func TestEncryptionWeakness(t *testing.T) {
+	numbers := loadNumbers("xmas_crypto.input.test1")
+
+	start, end, weakness, err := encryptionWeakness(numbers, 5)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, start)
+	assert.Equal(t, 6, end)
+	assert.Equal(t, 62, weakness)
+}
+
+func TestLoadNumbers(t *testing.T) {
+	// The last number is kept intact without a trailing newline
+	path := filepath.Join(t.TempDir(), "xmas_crypto.input")
+	assert.NoError(t, os.WriteFile(path, []byte("35\n20\n127"), 0644))
+	assert.Equal(t, []int{35, 20, 127}, loadNumbers(path))
+
+	assert.PanicsWithError(t, "open does-not-exist.input: no such file or directory", func() {
+		loadNumbers("does-not-exist.input")
+	})
+}
@@ -0,0 +1,137 @@
+// Package ksum finds k numbers within a list which sum up to a given target.
+package ksum
+
+import (
+	"fmt"
+	"sort"
+)
+
+// FindKWithSum returns the first k numbers within numbers which sum up to
+// target, in ascending order.
+//
+// Each element of numbers is used at most once, so a value can only appear in
+// the solution as often as it appears in numbers. Returns an error if there is
+// no solution, or if k is not positive.
+func FindKWithSum(numbers []int, k int, target int) ([]int, error) {
+	if k < 1 {
+		return nil, fmt.Errorf("Invalid number of summands: %d", k)
+	}
+
+	var solution []int
+	find(sorted(numbers), k, target, func(summands []int) bool {
+		solution = append([]int{}, summands...)
+		return false
+	})
+
+	if solution == nil {
+		return nil, fmt.Errorf("No %d numbers with sum %d found", k, target)
+	}
+
+	return solution, nil
+}
+
+// FindAllKWithSum returns all distinct solutions of FindKWithSum, in
+// lexicographical order.
+//
+// Solutions are distinct by value, so picking different elements of equal
+// value yields one solution only.
+func FindAllKWithSum(numbers []int, k int, target int) [][]int {
+	if k < 1 {
+		return nil
+	}
+
+	var solutions [][]int
+	find(sorted(numbers), k, target, func(summands []int) bool {
+		solutions = append(solutions, append([]int{}, summands...))
+		return true
+	})
+
+	return solutions
+}
+
+func sorted(numbers []int) []int {
+	sorted := append([]int{}, numbers...)
+	sort.Ints(sorted)
+
+	return sorted
+}
+
+// Call visit with each distinct solution within the sorted slice of numbers,
+// until it returns false. Returns false if the search was stopped.
+func find(numbers []int, k int, target int, visit func([]int) bool) bool {
+	summands := make([]int, 0, k)
+	return findRecurse(numbers, k, target, summands, visit)
+}
+
+// This is the recursion body of `find`, not to be used directly. `summands`
+// holds the numbers picked so far.
+func findRecurse(numbers []int, k int, target int, summands []int, visit func([]int) bool) bool {
+	if k == 1 {
+		idx := sort.SearchInts(numbers, target)
+		if idx < len(numbers) && numbers[idx] == target {
+			return visit(append(summands, target))
+		}
+
+		return true
+	}
+
+	if k == 2 {
+		return findPair(numbers, target, summands, visit)
+	}
+
+	for i := 0; i <= len(numbers)-k; i += 1 {
+		// Picking the same value as the previous iteration would only
+		// yield solutions found already.
+		if i > 0 && numbers[i] == numbers[i-1] {
+			continue
+		}
+
+		// Later elements are only larger, so we can stop as soon as
+		// even the smallest choice overshoots. This only holds for
+		// non-negative numbers, as a negative number could compensate.
+		if numbers[i] >= 0 && numbers[i]*k > target {
+			break
+		}
+
+		if !findRecurse(numbers[i+1:], k-1, target-numbers[i], append(summands, numbers[i]), visit) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Two-pointer search for pairs within the sorted slice of numbers: If the
+// current pair's sum is too small, the only way to increase it is moving the
+// lower pointer up, and vice versa.
+func findPair(numbers []int, target int, summands []int, visit func([]int) bool) bool {
+	lo, hi := 0, len(numbers)-1
+
+	for lo < hi {
+		sum := numbers[lo] + numbers[hi]
+
+		switch {
+		case sum < target:
+			lo += 1
+		case sum > target:
+			hi -= 1
+		default:
+			if !visit(append(summands, numbers[lo], numbers[hi])) {
+				return false
+			}
+
+			// Skip over equal values, which would yield the same
+			// solution again.
+			for lo < hi && numbers[lo] == numbers[lo+1] {
+				lo += 1
+			}
+			for lo < hi && numbers[hi] == numbers[hi-1] {
+				hi -= 1
+			}
+			lo += 1
+			hi -= 1
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,108 @@
+package ksum
+
+import (
+	"fmt"
+	"math/rand"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFindKWithSum(t *testing.T) {
+	expenses := []int{1721, 979, 366, 299, 675, 1456}
+
+	pair, err := FindKWithSum(expenses, 2, 2020)
+	assert.NoError(t, err)
+	assert.Equal(t, []int{299, 1721}, pair)
+
+	triplet, err := FindKWithSum(expenses, 3, 2020)
+	assert.NoError(t, err)
+	assert.Equal(t, []int{366, 675, 979}, triplet)
+
+	_, err = FindKWithSum(expenses, 2, 1)
+	assert.Error(t, err)
+
+	_, err = FindKWithSum(expenses, 0, 0)
+	assert.Error(t, err)
+
+	_, err = FindKWithSum(expenses, 7, 2020)
+	assert.Error(t, err)
+
+	single, err := FindKWithSum(expenses, 1, 366)
+	assert.NoError(t, err)
+	assert.Equal(t, []int{366}, single)
+}
+
+func TestFindKWithSumMultiplicity(t *testing.T) {
+	// Elements must not be reused
+	_, err := FindKWithSum([]int{1010, 5}, 2, 2020)
+	assert.Error(t, err)
+	_, err = FindKWithSum([]int{1, 2, 673, 674}, 3, 2020)
+	assert.Error(t, err)
+
+	// But equal values at distinct positions are fine
+	pair, err := FindKWithSum([]int{1010, 5, 1010}, 2, 2020)
+	assert.NoError(t, err)
+	assert.Equal(t, []int{1010, 1010}, pair)
+
+	// Input is left untouched
+	numbers := []int{3, 1, 2}
+	_, err = FindKWithSum(numbers, 2, 5)
+	assert.NoError(t, err)
+	assert.Equal(t, []int{3, 1, 2}, numbers)
+}
+
+func TestFindAllKWithSum(t *testing.T) {
+	assert.Equal(
+		t,
+		[][]int{{-3, 0, 3}, {-3, 1, 2}, {-1, 0, 1}},
+		FindAllKWithSum([]int{1, 0, -1, 2, -3, 3, 1}, 3, 0),
+	)
+
+	// Duplicate values yield distinct solutions once only
+	assert.Equal(t, [][]int{{2, 2}}, FindAllKWithSum([]int{2, 2, 2, 2}, 2, 4))
+	assert.Equal(t, [][]int{{1, 1, 1, 2}}, FindAllKWithSum([]int{1, 1, 1, 2, 1}, 4, 5))
+
+	assert.Nil(t, FindAllKWithSum([]int{1, 2}, 3, 3))
+	assert.Nil(t, FindAllKWithSum([]int{1, 2}, 0, 0))
+}
+
+// Compare against exhaustive search over all subsets of small inputs.
+func TestFindAllKWithSumBruteForce(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+
+	for round := 0; round < 200; round += 1 {
+		numbers := make([]int, rng.Intn(10))
+		for i := range numbers {
+			numbers[i] = rng.Intn(11) - 5
+		}
+		k := 1 + rng.Intn(4)
+		target := rng.Intn(11) - 5
+
+		expected := make(map[string]bool)
+		for subset := 0; subset < 1<<len(numbers); subset += 1 {
+			var summands []int
+			sum := 0
+			for i, x := range numbers {
+				if subset&(1<<i) != 0 {
+					summands = append(summands, x)
+					sum += x
+				}
+			}
+
+			if len(summands) == k && sum == target {
+				sort.Ints(summands)
+				expected[fmt.Sprint(summands)] = true
+			}
+		}
+
+		actual := make(map[string]bool)
+		for _, solution := range FindAllKWithSum(numbers, k, target) {
+			assert.False(t, actual[fmt.Sprint(solution)], "Duplicate solution %v", solution)
+			actual[fmt.Sprint(solution)] = true
+		}
+
+		assert.Equal(t, expected, actual, "%v, k = %d, target = %d", numbers, k, target)
+	}
+}
@@ -0,0 +1,134 @@
+package numtheory
+
+import (
+	"fmt"
+	"math/big"
+)
+
+// CRT solves the system of congruences x == remainders[i] mod moduli[i] by
+// means of the Chinese Remainder Theorem.
+//
+// The moduli need not be pairwise coprime. If they aren't, the system might not
+// have a solution, in which case an error is returned. An error is also
+// returned if any modulus isn't positive, or if the LCM of the moduli
+// overflows. Use `CRTBig` if the latter is a concern.
+//
+// Returns the smallest non-negative solution x, as well as the modulus (the
+// LCM of all moduli) modulo which the solution is unique. An empty system is
+// solved by x = 0 mod 1.
+func CRT[T Integer](remainders []T, moduli []T) (x T, modulus T, err error) {
+	if len(remainders) != len(moduli) {
+		return 0, 0, fmt.Errorf("Got %d remainders but %d moduli", len(remainders), len(moduli))
+	}
+
+	x, modulus = 0, 1
+
+	for i := range moduli {
+		if moduli[i] <= 0 {
+			return 0, 0, fmt.Errorf("Modulus must be positive: %v", moduli[i])
+		}
+
+		x, modulus, err = mergeCongruences(x, modulus, Mod(remainders[i], moduli[i]), moduli[i])
+		if err != nil {
+			return 0, 0, err
+		}
+	}
+
+	return x, modulus, nil
+}
+
+// Merge the two congruences x == a1 mod m1 and x == a2 mod m2, with a1 and a2
+// already reduced, into a single one x == a mod lcm(m1, m2).
+func mergeCongruences[T Integer](a1, m1, a2, m2 T) (T, T, error) {
+	// Any solution is of the form x = a1 + k * m1, so we need
+	//   a1 + k * m1 == a2 mod m2
+	//   <=> k * m1 == a2 - a1 mod m2
+	// which has a solution iff g = gcd(m1, m2) divides a2 - a1. If so,
+	//   k == (a2 - a1) / g * (m1 / g)^-1 mod (m2 / g)
+	// As g divides m2, it is sufficient to check whether it divides the
+	// difference modulo m2, which conveniently never overflows.
+	g := GCD(m1, m2)
+	diff := subMod(a2, Mod(a1, m2), m2)
+	if diff%g != 0 {
+		return 0, 0, fmt.Errorf(
+			"Inconsistent congruences: x == %v mod %v and x == %v mod %v",
+			a1, m1, a2, m2,
+		)
+	}
+
+	reducedM2 := m2 / g
+	lcm, err := checkedMul(m1, reducedM2)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	inverse, err := ModInverse(m1/g, reducedM2)
+	if err != nil {
+		// m1 / g and m2 / g are coprime by construction
+		panic(fmt.Sprintf("No inverse of %v modulo %v: %v", m1/g, reducedM2, err))
+	}
+	k := mulMod(Mod(diff/g, reducedM2), inverse, reducedM2)
+
+	// As k < m2 / g, a1 + k * m1 < m1 + (m2 / g - 1) * m1 = lcm, so
+	// neither of these overflow.
+	return a1 + k*m1, lcm, nil
+}
+
+// Congruence is a single congruence x == Remainder mod Modulus, of
+// arbitrary size.
+type Congruence struct {
+	Remainder *big.Int
+	Modulus   *big.Int
+}
+
+func (congruence Congruence) String() string {
+	return fmt.Sprintf("x == %s mod %s", congruence.Remainder, congruence.Modulus)
+}
+
+// CRTBig is like `CRT`, but for congruences of arbitrary size.
+func CRTBig(congruences []Congruence) (x *big.Int, modulus *big.Int, err error) {
+	x, modulus = big.NewInt(0), big.NewInt(1)
+
+	for _, congruence := range congruences {
+		if congruence.Modulus.Sign() <= 0 {
+			return nil, nil, fmt.Errorf("Modulus must be positive: %s", congruence)
+		}
+
+		x, modulus, err = mergeBigCongruences(x, modulus, congruence.Remainder, congruence.Modulus)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	return x, modulus, nil
+}
+
+// Like `mergeCongruences`, but for arbitrarily-sized, not necessarily reduced,
+// congruences.
+func mergeBigCongruences(a1, m1, a2, m2 *big.Int) (*big.Int, *big.Int, error) {
+	// See `mergeCongruences` for the derivation. With Bezout's identity
+	// p * m1 + q * m2 = g, p is the inverse of m1 / g modulo m2 / g.
+	p := new(big.Int)
+	g := new(big.Int).GCD(p, nil, m1, m2)
+
+	diff := new(big.Int).Sub(a2, a1)
+	quotient, remainder := new(big.Int).QuoRem(diff, g, new(big.Int))
+	if remainder.Sign() != 0 {
+		return nil, nil, fmt.Errorf(
+			"Inconsistent congruences: x == %s mod %s and x == %s mod %s",
+			a1, m1, a2, m2,
+		)
+	}
+
+	reducedM2 := new(big.Int).Quo(m2, g)
+	k := new(big.Int).Mul(quotient, p)
+	// Mod, unlike Rem, is the mathematical modulus, ie always non-negative
+	k.Mod(k, reducedM2)
+
+	lcm := new(big.Int).Mul(m1, reducedM2)
+	x := new(big.Int).Mul(k, m1)
+	x.Add(x, a1)
+	x.Mod(x, lcm)
+
+	return x, lcm, nil
+}
@@ -0,0 +1,170 @@
+package numtheory
+
+import (
+	"math"
+	"math/big"
+	"testing"
+	"testing/quick"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCRT(t *testing.T) {
+	{
+		x, modulus, err := CRT([]int{2, 3, 2}, []int{3, 5, 7})
+		assert.NoError(t, err)
+		assert.Equal(t, 23, x)
+		assert.Equal(t, 105, modulus)
+	}
+
+	{
+		// Moduli which aren't coprime
+		x, modulus, err := CRT([]int{4, 2}, []int{6, 8})
+		assert.NoError(t, err)
+		assert.Equal(t, 10, x)
+		assert.Equal(t, 24, modulus)
+	}
+
+	{
+		// x == 1 mod 4 implies x being odd, contradicting x == 0 mod 6
+		_, _, err := CRT([]int{1, 0}, []int{4, 6})
+		assert.Error(t, err)
+	}
+
+	{
+		x, modulus, err := CRT([]int{-1, -12}, []int{3, 5})
+		assert.NoError(t, err)
+		assert.Equal(t, 8, x)
+		assert.Equal(t, 15, modulus)
+	}
+
+	{
+		x, modulus, err := CRT([]uint8{}, []uint8{})
+		assert.NoError(t, err)
+		assert.Equal(t, uint8(0), x)
+		assert.Equal(t, uint8(1), modulus)
+	}
+
+	{
+		_, _, err := CRT([]int{1}, []int{0})
+		assert.Error(t, err)
+	}
+
+	{
+		_, _, err := CRT([]int{1, 2}, []int{3})
+		assert.Error(t, err)
+	}
+
+	{
+		// LCM of the moduli overflows
+		_, _, err := CRT([]int64{1, 2}, []int64{math.MaxInt64 - 1, math.MaxInt64})
+		assert.Error(t, err)
+	}
+}
+
+func TestCRTProperties(t *testing.T) {
+	property := func(remainders [3]int16, moduli [3]uint8) bool {
+		// Small moduli keep the brute-force search below fast, and make
+		// shared factors likely.
+		var r, m []int64
+		for i := range moduli {
+			if moduli[i]%32 == 0 {
+				continue
+			}
+			r = append(r, int64(remainders[i]))
+			m = append(m, int64(moduli[i]%32))
+		}
+
+		x, modulus, err := CRT(r, m)
+
+		// Brute-force the smallest solution, which - if existing - is
+		// smaller than the LCM of the moduli.
+		lcm, _ := LCM(m...)
+		for candidate := int64(0); candidate < lcm; candidate += 1 {
+			solves := true
+			for i := range m {
+				solves = solves && Mod(candidate-r[i], m[i]) == 0
+			}
+
+			if solves {
+				return err == nil && x == candidate && modulus == lcm
+			}
+		}
+
+		return err != nil
+	}
+
+	assert.NoError(t, quick.Check(property, nil))
+}
+
+func TestCRTBig(t *testing.T) {
+	congruence := func(remainder int64, modulus int64) Congruence {
+		return Congruence{Remainder: big.NewInt(remainder), Modulus: big.NewInt(modulus)}
+	}
+
+	{
+		x, modulus, err := CRTBig([]Congruence{congruence(2, 3), congruence(3, 5), congruence(2, 7)})
+		assert.NoError(t, err)
+		assert.Equal(t, big.NewInt(23), x)
+		assert.Equal(t, big.NewInt(105), modulus)
+	}
+
+	{
+		x, modulus, err := CRTBig([]Congruence{congruence(4, 6), congruence(2, 8)})
+		assert.NoError(t, err)
+		assert.Equal(t, big.NewInt(10), x)
+		assert.Equal(t, big.NewInt(24), modulus)
+	}
+
+	{
+		_, _, err := CRTBig([]Congruence{congruence(1, 4), congruence(0, 6)})
+		assert.Error(t, err)
+	}
+
+	{
+		_, _, err := CRTBig([]Congruence{congruence(1, 0)})
+		assert.Error(t, err)
+	}
+
+	{
+		// LCM of the moduli exceeds 64 bits
+		x, modulus, err := CRTBig([]Congruence{
+			congruence(1, math.MaxInt64-1),
+			congruence(2, math.MaxInt64),
+		})
+		assert.NoError(t, err)
+
+		expectedModulus := new(big.Int).Mul(big.NewInt(math.MaxInt64-1), big.NewInt(math.MaxInt64))
+		assert.Equal(t, expectedModulus, modulus)
+		assert.Equal(t, int64(1), new(big.Int).Mod(x, big.NewInt(math.MaxInt64-1)).Int64())
+		assert.Equal(t, int64(2), new(big.Int).Mod(x, big.NewInt(math.MaxInt64)).Int64())
+	}
+}
+
+func TestCRTMatchesCRTBig(t *testing.T) {
+	property := func(remainders [3]int32, moduli [3]uint16) bool {
+		var r, m []int64
+		var congruences []Congruence
+		for i := range moduli {
+			if moduli[i] == 0 {
+				continue
+			}
+			r = append(r, int64(remainders[i]))
+			m = append(m, int64(moduli[i]))
+			congruences = append(congruences, Congruence{
+				Remainder: big.NewInt(int64(remainders[i])),
+				Modulus:   big.NewInt(int64(moduli[i])),
+			})
+		}
+
+		x, modulus, err := CRT(r, m)
+		bigX, bigModulus, bigErr := CRTBig(congruences)
+		if err != nil || bigErr != nil {
+			return err != nil && bigErr != nil
+		}
+
+		return bigX.Cmp(big.NewInt(x)) == 0 && bigModulus.Cmp(big.NewInt(modulus)) == 0
+	}
+
+	assert.NoError(t, quick.Check(property, nil))
+}
@@ -0,0 +1,210 @@
+// Package numtheory provides modular arithmetic and other number-theoretic
+// helpers, as needed by a surprising number of puzzles.
+package numtheory
+
+import (
+	"fmt"
+	"math/bits"
+)
+
+// Signed is the set of signed integer types.
+type Signed interface {
+	~int | ~int8 | ~int16 | ~int32 | ~int64
+}
+
+// Unsigned is the set of unsigned integer types.
+type Unsigned interface {
+	~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 | ~uintptr
+}
+
+// Integer is the set of all integer types.
+type Integer interface {
+	Signed | Unsigned
+}
+
+// Abs returns the absolute value of x.
+func Abs[T Integer](x T) T {
+	if x < 0 {
+		return -x
+	}
+
+	return x
+}
+
+// Mod returns a modulo b, which - unlike Go's built-in % operation, which is a
+// remainder - is always in [0, |b|). As an example, -1 % 3 = -1 whereas
+// Mod(-1, 3) = 2.
+//
+// Panics if b is zero.
+func Mod[T Integer](a, b T) T {
+	m := a % b
+
+	if m < 0 {
+		m += Abs(b)
+	}
+
+	return m
+}
+
+// GCD returns the non-negative greatest common divisor of a and b.
+func GCD[T Integer](a, b T) T {
+	a, b = Abs(a), Abs(b)
+
+	for b != 0 {
+		a, b = b, a%b
+	}
+
+	return a
+}
+
+// ExtGCD implements the extended Euclidean algorithm. It returns the
+// non-negative greatest common divisor g of a and b, as well as Bezout
+// coefficients x and y such that a * x + b * y = g.
+func ExtGCD[T Signed](a, b T) (g, x, y T) {
+	// Invariants: a * oldX + b * oldY = oldR and a * x + b * y = r
+	oldR, r := a, b
+	oldX, x := T(1), T(0)
+	oldY, y := T(0), T(1)
+
+	for r != 0 {
+		quotient := oldR / r
+		oldR, r = r, oldR-quotient*r
+		oldX, x = x, oldX-quotient*x
+		oldY, y = y, oldY-quotient*y
+	}
+
+	if oldR < 0 {
+		return -oldR, -oldX, -oldY
+	}
+
+	return oldR, oldX, oldY
+}
+
+// ModInverse returns the multiplicative inverse of a modulo m, ie the x in
+// [0, m) with a * x == 1 mod m.
+//
+// Returns an error if m isn't positive, or if a and m aren't coprime, in which
+// case no inverse exists.
+func ModInverse[T Integer](a, m T) (T, error) {
+	if m <= 0 {
+		return 0, fmt.Errorf("Modulus must be positive: %v", m)
+	}
+
+	// Extended Euclidean algorithm, tracking only the coefficient of a.
+	// Coefficients are kept modulo m, so this works for unsigned types
+	// too, which can't represent negative coefficients.
+	oldR, r := m, Mod(a, m)
+	oldX, x := T(0), T(1)
+
+	for r != 0 {
+		quotient := oldR / r
+		oldR, r = r, oldR-quotient*r
+		oldX, x = x, subMod(oldX, mulMod(quotient%m, x, m), m)
+	}
+
+	if oldR != 1 {
+		return 0, fmt.Errorf("%v has no inverse modulo %v, gcd is %v", a, m, oldR)
+	}
+
+	return Mod(oldX, m), nil
+}
+
+// ModPow returns base^exp mod m, by means of square-and-multiply.
+//
+// Negative exponents are supported if base has an inverse modulo m. Returns
+// an error if m isn't positive, or if exp is negative and base has no
+// inverse.
+func ModPow[T Integer](base, exp, m T) (T, error) {
+	if m <= 0 {
+		return 0, fmt.Errorf("Modulus must be positive: %v", m)
+	}
+
+	base = Mod(base, m)
+	if exp < 0 {
+		inverse, err := ModInverse(base, m)
+		if err != nil {
+			return 0, err
+		}
+
+		base, exp = inverse, -exp
+	}
+
+	result := Mod(1, m)
+	for exp > 0 {
+		if exp%2 == 1 {
+			result = mulMod(result, base, m)
+		}
+
+		base = mulMod(base, base, m)
+		exp /= 2
+	}
+
+	return result, nil
+}
+
+// LCM returns the non-negative least common multiple of the given numbers.
+// The LCM of no numbers is 1, the LCM of any set containing 0 is 0.
+//
+// Returns an error if the result overflows.
+func LCM[T Integer](numbers ...T) (T, error) {
+	lcm := T(1)
+
+	for _, x := range numbers {
+		x = Abs(x)
+		if x == 0 {
+			return 0, nil
+		}
+
+		// lcm(a, b) = a / gcd(a, b) * b, dividing first to keep
+		// intermediate results small.
+		factor := x / GCD(lcm, x)
+		product, err := checkedMul(lcm, factor)
+		if err != nil {
+			return 0, err
+		}
+
+		lcm = product
+	}
+
+	return lcm, nil
+}
+
+// Returns a * b for non-negative a and b, or an error if the product
+// overflows.
+func checkedMul[T Integer](a, b T) (T, error) {
+	if a == 0 || b == 0 {
+		return 0, nil
+	}
+
+	product := a * b
+	if product/b != a || product < 0 {
+		return 0, fmt.Errorf("Integer overflow: %v * %v", a, b)
+	}
+
+	return product, nil
+}
+
+// Returns a * b mod m for a, b in [0, m). Intermediate results are 128 bits
+// wide, so this never overflows.
+func mulMod[T Integer](a, b, m T) T {
+	hi, lo := bits.Mul64(uint64(a), uint64(b))
+	return T(bits.Rem64(hi, lo, uint64(m)))
+}
+
+// Returns a + b mod m for a, b in [0, m), without ever leaving that range.
+func addMod[T Integer](a, b, m T) T {
+	if a >= m-b {
+		return a - (m - b)
+	}
+
+	return a + b
+}
+
+// Returns a - b mod m for a, b in [0, m), without ever leaving that range.
+func subMod[T Integer](a, b, m T) T {
+	if a >= b {
+		return a - b
+	}
+
+	return m - (b - a)
+}
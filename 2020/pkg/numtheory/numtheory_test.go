@@ -0,0 +1,206 @@
+package numtheory
+
+import (
+	"math"
+	"math/big"
+	"testing"
+	"testing/quick"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMod(t *testing.T) {
+	assert.Equal(t, 2, Mod(-1, 3))
+	assert.Equal(t, 2, Mod(-1, -3))
+	assert.Equal(t, 0, Mod(-360, 360))
+	assert.Equal(t, 90, Mod(450, 360))
+	assert.Equal(t, uint8(4), Mod(uint8(254), uint8(10)))
+}
+
+func TestModProperties(t *testing.T) {
+	property := func(a int64, b int64) bool {
+		// |MinInt64| isn't representable
+		if b == 0 || b == math.MinInt64 {
+			return true
+		}
+
+		// big.Int's Mod is the Euclidean modulus, ie in [0, |b|) too
+		expected := new(big.Int).Mod(big.NewInt(a), big.NewInt(b))
+		return expected.Cmp(big.NewInt(Mod(a, b))) == 0
+	}
+
+	assert.NoError(t, quick.Check(property, nil))
+}
+
+func TestGCD(t *testing.T) {
+	assert.Equal(t, 6, GCD(12, 18))
+	assert.Equal(t, 6, GCD(-12, 18))
+	assert.Equal(t, 5, GCD(0, 5))
+	assert.Equal(t, 0, GCD(0, 0))
+	assert.Equal(t, uint16(1), GCD(uint16(17), uint16(31)))
+}
+
+func TestExtGCDProperties(t *testing.T) {
+	property := func(a int32, b int32) bool {
+		g, x, y := ExtGCD(int64(a), int64(b))
+
+		divides := g == 0 || int64(a)%g == 0 && int64(b)%g == 0
+		return g == GCD(int64(a), int64(b)) && int64(a)*x+int64(b)*y == g && divides
+	}
+
+	assert.NoError(t, quick.Check(property, nil))
+}
+
+func TestModInverse(t *testing.T) {
+	{
+		x, err := ModInverse(3, 7)
+		assert.NoError(t, err)
+		assert.Equal(t, 5, x)
+	}
+
+	{
+		x, err := ModInverse(-3, 7)
+		assert.NoError(t, err)
+		assert.Equal(t, 2, x)
+	}
+
+	{
+		x, err := ModInverse(uint8(200), uint8(251))
+		assert.NoError(t, err)
+		assert.Equal(t, uint8(1), uint8(uint(x)*200%251))
+	}
+
+	{
+		_, err := ModInverse(4, 6)
+		assert.Error(t, err)
+	}
+
+	{
+		_, err := ModInverse(4, 0)
+		assert.Error(t, err)
+	}
+}
+
+func TestModInverseProperties(t *testing.T) {
+	property := func(a int64, m int64) bool {
+		m = Abs(m%1000000007) + 1
+
+		x, err := ModInverse(a, m)
+		if GCD(a, m) != 1 {
+			return err != nil
+		}
+
+		product := new(big.Int).Mul(big.NewInt(a), big.NewInt(x))
+		return err == nil && x >= 0 && x < m && product.Mod(product, big.NewInt(m)).Cmp(big.NewInt(Mod(1, m))) == 0
+	}
+
+	assert.NoError(t, quick.Check(property, nil))
+}
+
+func TestModPow(t *testing.T) {
+	{
+		x, err := ModPow(4, 13, 497)
+		assert.NoError(t, err)
+		assert.Equal(t, 445, x)
+	}
+
+	{
+		// 3^-1 = 5 mod 7
+		x, err := ModPow(3, -1, 7)
+		assert.NoError(t, err)
+		assert.Equal(t, 5, x)
+	}
+
+	{
+		x, err := ModPow(5, 0, 1)
+		assert.NoError(t, err)
+		assert.Equal(t, 0, x)
+	}
+
+	{
+		// Intermediate products exceed 64 bits
+		x, err := ModPow(uint64(math.MaxUint64-1), uint64(2), uint64(math.MaxUint64))
+		assert.NoError(t, err)
+		assert.Equal(t, uint64(1), x)
+	}
+
+	{
+		_, err := ModPow(2, -1, 4)
+		assert.Error(t, err)
+	}
+}
+
+func TestModPowProperties(t *testing.T) {
+	property := func(base int64, exp uint16, m int64) bool {
+		m = Abs(m%math.MaxInt64) + 1
+
+		x, err := ModPow(base, int64(exp), m)
+		expected := new(big.Int).Exp(big.NewInt(base), big.NewInt(int64(exp)), big.NewInt(m))
+		// big.Int.Exp returns a value with the sign of x for negative x
+		expected.Mod(expected, big.NewInt(m))
+		return err == nil && expected.Cmp(big.NewInt(x)) == 0
+	}
+
+	assert.NoError(t, quick.Check(property, nil))
+}
+
+func TestLCM(t *testing.T) {
+	{
+		lcm, err := LCM(4, 6, 10)
+		assert.NoError(t, err)
+		assert.Equal(t, 60, lcm)
+	}
+
+	{
+		lcm, err := LCM[int]()
+		assert.NoError(t, err)
+		assert.Equal(t, 1, lcm)
+	}
+
+	{
+		lcm, err := LCM(-4, 6, 0)
+		assert.NoError(t, err)
+		assert.Equal(t, 0, lcm)
+	}
+
+	{
+		_, err := LCM(int8(16), int8(9))
+		assert.Error(t, err)
+	}
+}
+
+func TestLCMProperties(t *testing.T) {
+	property := func(numbers []uint8) bool {
+		lcm, err := LCM(numbers...)
+		if err != nil {
+			// Overflow is possible with enough numbers, but not
+			// with arbitrary precision.
+			return true
+		}
+
+		// lcm is a multiple of each number...
+		for _, x := range numbers {
+			if x == 0 {
+				return lcm == 0
+			}
+			if lcm%x != 0 {
+				return false
+			}
+		}
+
+		// ... and no smaller number is.
+		for candidate := uint8(1); candidate < lcm; candidate += 1 {
+			multiple := true
+			for _, x := range numbers {
+				multiple = multiple && candidate%x == 0
+			}
+			if multiple {
+				return false
+			}
+		}
+
+		return true
+	}
+
+	assert.NoError(t, quick.Check(property, nil))
+}
@@ -0,0 +1,126 @@
+package numtheory
+
+import "sort"
+
+// Bases for which the Miller-Rabin test is known to be deterministic for all
+// 64-bit integers.
+var millerRabinBases = []uint64{2, 3, 5, 7, 11, 13, 17, 19, 23, 29, 31, 37}
+
+// IsPrime returns whether n is prime. Numbers smaller than 2 are not.
+//
+// Uses a deterministic variant of the Miller-Rabin test, so this is fast even
+// for large 64-bit numbers.
+func IsPrime[T Integer](n T) bool {
+	if n < 2 {
+		return false
+	}
+
+	return isPrime(uint64(n))
+}
+
+func isPrime(n uint64) bool {
+	for _, p := range millerRabinBases {
+		if n%p == 0 {
+			return n == p
+		}
+	}
+
+	// Write n - 1 = d * 2^s with d odd
+	d, s := n-1, 0
+	for d%2 == 0 {
+		d /= 2
+		s += 1
+	}
+
+	for _, a := range millerRabinBases {
+		// ModPow can't fail for positive moduli and exponents
+		x, _ := ModPow(a, d, n)
+		if x == 1 || x == n-1 {
+			continue
+		}
+
+		composite := true
+		for i := 1; i < s; i += 1 {
+			x = mulMod(x, x, n)
+			if x == n-1 {
+				composite = false
+				break
+			}
+		}
+
+		if composite {
+			// a is a witness for n being composite
+			return false
+		}
+	}
+
+	return true
+}
+
+// Factorize returns the prime factors of n in ascending order, with each
+// factor repeated according to its multiplicity. As an example, the factors
+// of 12 are [2, 2, 3].
+//
+// Numbers smaller than 2 have no prime factors.
+func Factorize[T Integer](n T) []T {
+	var factors []T
+	if n < 2 {
+		return factors
+	}
+
+	for _, factor := range factorize(uint64(n)) {
+		factors = append(factors, T(factor))
+	}
+	sort.Slice(factors, func(i, j int) bool { return factors[i] < factors[j] })
+
+	return factors
+}
+
+func factorize(n uint64) []uint64 {
+	var factors []uint64
+
+	// Trial division takes care of small factors, which are the common
+	// case, quickly.
+	for p := uint64(2); p < 1000 && p*p <= n; p += 1 {
+		for n%p == 0 {
+			factors = append(factors, p)
+			n /= p
+		}
+	}
+
+	if n == 1 {
+		return factors
+	}
+
+	if isPrime(n) {
+		return append(factors, n)
+	}
+
+	// Remaining factors are large, so we split them with Pollard's rho
+	divisor := pollardRho(n)
+	factors = append(factors, factorize(divisor)...)
+	return append(factors, factorize(n/divisor)...)
+}
+
+// Find a non-trivial divisor of the composite, odd number n by means of
+// Pollard's rho algorithm with Floyd's cycle detection.
+func pollardRho(n uint64) uint64 {
+	// The pseudo-random sequence x -> x^2 + c mod n fails to find a divisor
+	// for some c, in which case we simply try the next one.
+	for c := uint64(1); ; c += 1 {
+		next := func(x uint64) uint64 {
+			return addMod(mulMod(x, x, n), c, n)
+		}
+
+		x, y, d := uint64(2), uint64(2), uint64(1)
+		for d == 1 {
+			x = next(x)
+			y = next(next(y))
+			d = GCD(max(x, y)-min(x, y), n)
+		}
+
+		if d != n {
+			return d
+		}
+	}
+}
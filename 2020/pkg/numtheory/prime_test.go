@@ -0,0 +1,63 @@
+package numtheory
+
+import (
+	"math"
+	"math/big"
+	"testing"
+	"testing/quick"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsPrime(t *testing.T) {
+	var primes []int
+	for n := -5; n < 50; n += 1 {
+		if IsPrime(n) {
+			primes = append(primes, n)
+		}
+	}
+	assert.Equal(t, []int{2, 3, 5, 7, 11, 13, 17, 19, 23, 29, 31, 37, 41, 43, 47}, primes)
+
+	// Largest 64-bit prime, and a strong pseudoprime to several bases
+	assert.True(t, IsPrime(uint64(math.MaxUint64-58)))
+	assert.False(t, IsPrime(uint64(3215031751)))
+	assert.True(t, IsPrime(int32(math.MaxInt32)))
+	assert.False(t, IsPrime(uint8(255)))
+}
+
+func TestIsPrimeProperties(t *testing.T) {
+	property := func(n uint64) bool {
+		return IsPrime(n) == new(big.Int).SetUint64(n).ProbablyPrime(20)
+	}
+
+	assert.NoError(t, quick.Check(property, nil))
+}
+
+func TestFactorize(t *testing.T) {
+	assert.Equal(t, []int{2, 2, 3}, Factorize(12))
+	assert.Empty(t, Factorize(1))
+	assert.Empty(t, Factorize(-12))
+	assert.Equal(t, []uint8{3, 5, 17}, Factorize(uint8(255)))
+
+	// Product of two large primes, which trial division won't find
+	assert.Equal(t, []uint64{4294967291, 4294967291}, Factorize(uint64(4294967291)*4294967291))
+	assert.Equal(t, []int64{1000003, 1000033, 1000037}, Factorize(int64(1000003)*1000033*1000037))
+}
+
+func TestFactorizeProperties(t *testing.T) {
+	property := func(n uint64) bool {
+		factors := Factorize(n)
+
+		product := uint64(1)
+		for i, factor := range factors {
+			if !IsPrime(factor) || i > 0 && factors[i-1] > factor {
+				return false
+			}
+			product *= factor
+		}
+
+		return n < 2 && len(factors) == 0 || product == n
+	}
+
+	assert.NoError(t, quick.Check(property, nil))
+}